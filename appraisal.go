@@ -26,21 +26,40 @@ type ItemsAndTotals struct {
 	Items  []AppraisalItem `json:"items"`
 }
 
+// ItemGroup is a part of an appraisal that is priced on its own, like one fit out of several
+type ItemGroup struct {
+	Name   string          `json:"name"`
+	Ship   string          `json:"ship,omitempty"`
+	Player string          `json:"player,omitempty"`
+	Totals Totals          `json:"totals"`
+	Items  []AppraisalItem `json:"items"`
+}
+
 type Appraisal struct {
-	ID           string         `json:"id,omitempty"`
-	Created      int64          `json:"created"`
-	Kind         string         `json:"kind"`
-	MarketName   string         `json:"market_name"`
-	Original     ItemsAndTotals `json:"original"`
-	Buyback      ItemsAndTotals `json:"buyback"`
-	BuybackCap   float64        `json:"buyback_cap,omitempty"`
-	Raw          string         `json:"raw"`
-	Unparsed     map[int]string `json:"unparsed"`
-	OwnerID      int64          `json:"owner_id,omitempty"`
-	User         *User          `json:"user,omitempty"`
-	Private      bool           `json:"private"`
-	PrivateToken string         `json:"private_token,omitempty"`
-	UserName     string         `json:"user_name,omitempty"`
+	ID           string                 `json:"id,omitempty"`
+	Created      int64                  `json:"created"`
+	Kind         string                 `json:"kind"`
+	MarketName   string                 `json:"market_name"`
+	Original     ItemsAndTotals         `json:"original"`
+	Buyback      ItemsAndTotals         `json:"buyback"`
+	Groups       []ItemGroup            `json:"groups,omitempty"`
+	Orders       []AppraisalItem        `json:"orders,omitempty"`
+	BuybackCap   float64                `json:"buyback_cap,omitempty"`
+	Raw          string                 `json:"raw"`
+	Unparsed     map[int]string         `json:"unparsed"`
+	Parse        []parsers.LineDecision `json:"parse,omitempty"`
+	OwnerID      int64                  `json:"owner_id,omitempty"`
+	User         *User                  `json:"user,omitempty"`
+	Private      bool                   `json:"private"`
+	PrivateToken string                 `json:"private_token,omitempty"`
+	UserName     string                 `json:"user_name,omitempty"`
+	SDEVersion   string                 `json:"sde_version,omitempty"`
+	Wallet       *WalletAnalytics       `json:"wallet,omitempty"`
+	DScan        *DScanAnalysis         `json:"dscan,omitempty"`
+	LootSplit    *LootSplit             `json:"loot_split,omitempty"`
+	PI           *PIPlan                `json:"pi,omitempty"`
+	Survey       *SurveyEstimate        `json:"survey,omitempty"`
+	Mining       *MiningTotals          `json:"mining,omitempty"`
 }
 
 func (appraisal *Appraisal) CreatedTime() time.Time {
@@ -81,12 +100,21 @@ type AppraisalItem struct {
 		Dropped    bool    `json:"dropped,omitempty"`
 		Destroyed  bool    `json:"destroyed,omitempty"`
 		Location   string  `json:"location,omitempty"`
+		Path       string  `json:"path,omitempty"`
 		PlayerName string  `json:"player_name,omitempty"`
 		Routed     bool    `json:"routed,omitempty"`
 		Volume     float64 `json:"volume,omitempty"`
 		Distance   string  `json:"distance,omitempty"`
 		BPC        bool    `json:"bpc"`
 		BPCRuns    int64   `json:"bpcRuns,omitempty"`
+		Retired    bool    `json:"retired,omitempty"`
+		OrderType  string  `json:"order_type,omitempty"`
+		OrderPrice float64 `json:"order_price,omitempty"`
+		JobRuns    int64   `json:"job_runs,omitempty"`
+		// Itemized wallet transactions
+		Transaction      string  `json:"transaction,omitempty"`
+		TransactionPrice float64 `json:"transaction_price,omitempty"`
+		Client           string  `json:"client,omitempty"`
 	} `json:"meta,omitempty"`
 }
 
@@ -396,13 +424,15 @@ func (appraisal *Appraisal) OnlyCompressedOre() bool {
 
 func (app *App) StringToAppraisal(market string, s string) (*Appraisal, error) {
 	appraisal := &Appraisal{
-		Created: time.Now().Unix(),
-		Raw:     s,
+		Created:    time.Now().Unix(),
+		Raw:        s,
+		SDEVersion: app.TypeDB.Version(),
 	}
 
 	result, unparsed := app.Parser(parsers.StringToInput(s))
 
 	appraisal.Unparsed = filterUnparsed(unparsed)
+	appraisal.Parse = parseDecisions(result)
 
 	kind, err := findKind(result)
 	if err != nil {
@@ -411,12 +441,57 @@ func (app *App) StringToAppraisal(market string, s string) (*Appraisal, error) {
 	appraisal.Kind = kind
 	appraisal.MarketName = market
 
+	app.priceParserResult(appraisal, result, market)
+	return appraisal, nil
+}
+
+// TableToAppraisal appraises the rows of an uploaded spreadsheet. Rows that couldn't be used are in Unparsed,
+// keyed by their row number.
+func (app *App) TableToAppraisal(market string, rows [][]string, columns parsers.TableColumns) (*Appraisal, error) {
+	raw := make([]string, len(rows))
+	for i, row := range rows {
+		raw[i] = strings.Join(row, "\t")
+	}
+
+	appraisal := &Appraisal{
+		Created:    time.Now().Unix(),
+		Raw:        strings.Join(raw, "\n"),
+		SDEVersion: app.TypeDB.Version(),
+	}
+
+	result, unparsed := parsers.ParseTable(rows, columns, app.TypeDB)
+	appraisal.Unparsed = filterUnparsed(unparsed)
+	if len(result.Items) == 0 {
+		return appraisal, ErrNoValidLinesFound
+	}
+	appraisal.Kind = result.Name()
+	appraisal.MarketName = market
+
+	app.priceParserResult(appraisal, result, market)
+	return appraisal, nil
+}
+
+// priceParserResult fills in the items, groups, buyback and the wallet, d-scan, PI or survey scan analysis of an
+// appraisal from a parser result
+func (app *App) priceParserResult(appraisal *Appraisal, result parsers.ParserResult, market string) {
 	appraisal.Original.Items = parserResultToAppraisalItems(result)
 	app.priceAppraisalItems(appraisal.Original.Items, &appraisal.Original.Totals, market, EmptyAdjustments)
 
 	appraisal.Original.Items, appraisal.Buyback = app.calculateBuyback(appraisal.Original.Items)
 
-	return appraisal, nil
+	appraisal.Groups = parserResultToGroups(result)
+	for i := range appraisal.Groups {
+		app.priceAppraisalItems(appraisal.Groups[i].Items, &appraisal.Groups[i].Totals, market, EmptyAdjustments)
+	}
+
+	appraisal.Orders = marketOrderItems(result)
+	app.priceAppraisalItems(appraisal.Orders, &Totals{}, market, EmptyAdjustments)
+
+	appraisal.Wallet = newWalletAnalytics(result, appraisal.Original.Items)
+	appraisal.DScan = app.newDScanAnalysis(result, appraisal.Original.Items)
+	appraisal.PI = app.newPIPlan(result, appraisal.Original.Items, market)
+	appraisal.Survey = app.newSurveyEstimate(result, market)
+	appraisal.Mining = newMiningTotals(result, appraisal.Original.Items)
 }
 
 func (app *App) priceAppraisalItems(items []AppraisalItem, totals *Totals, market string, adjustments map[int64]float64) {
@@ -428,6 +503,13 @@ func (app *App) priceAppraisalItems(items []AppraisalItem, totals *Totals, marke
 		}
 		items[i].TypeID = t.ID
 		items[i].TypeName = t.Name
+
+		// Job output is parsed as a number of runs, turn that into the number of produced items
+		if items[i].Extra.JobRuns != 0 {
+			items[i].Extra.JobRuns = items[i].Quantity
+			items[i].Quantity *= outputPerRun(t)
+		}
+
 		if t.PackagedVolume != 0.0 {
 			items[i].TypeVolume = t.PackagedVolume
 		} else {
@@ -461,6 +543,81 @@ func (app *App) priceAppraisalItems(items []AppraisalItem, totals *Totals, marke
 	}
 }
 
+// ResolvePinnedTypes fills in type information for items that the current type database doesn't know
+// about (anymore) from the static dump version that the appraisal was created with
+func (app *App) ResolvePinnedTypes(appraisal *Appraisal) {
+	if appraisal.SDEVersion == "" || app.TypeDBArchive == nil || appraisal.SDEVersion == app.TypeDB.Version() {
+		return
+	}
+
+	pinnedTypeDB, ok := app.TypeDBArchive.Get(appraisal.SDEVersion)
+	if !ok {
+		return
+	}
+
+	for i := range appraisal.Original.Items {
+		item := &appraisal.Original.Items[i]
+
+		var (
+			t     typedb.EveType
+			found bool
+		)
+		if item.TypeID != 0 {
+			if _, ok := app.TypeDB.GetTypeByID(item.TypeID); ok {
+				continue
+			}
+			t, found = pinnedTypeDB.GetTypeByID(item.TypeID)
+		} else {
+			if app.TypeDB.HasType(item.Name) {
+				continue
+			}
+			t, found = pinnedTypeDB.GetType(item.Name)
+		}
+
+		if !found {
+			continue
+		}
+
+		item.TypeID = t.ID
+		item.TypeName = t.Name
+		if item.TypeVolume == 0 {
+			item.TypeVolume = t.Volume
+		}
+		item.Extra.Retired = true
+	}
+}
+
+// outputPerRun returns how many items of the given type a single manufacturing run produces
+func outputPerRun(t typedb.EveType) int64 {
+	for _, product := range t.BlueprintProducts {
+		if product.TypeID == t.ID && product.Quantity > 0 {
+			return product.Quantity
+		}
+	}
+	return 1
+}
+
+// ParseReport says which parser took each line of a paste and why
+type ParseReport struct {
+	Kind  string                 `json:"kind"`
+	Lines []parsers.LineDecision `json:"lines"`
+}
+
+// ParseString runs the parser over the text without appraising anything
+func (app *App) ParseString(s string) ParseReport {
+	result, _ := app.Parser(parsers.StringToInput(s))
+	kind, _ := findKind(result)
+	return ParseReport{Kind: kind, Lines: parseDecisions(result)}
+}
+
+func parseDecisions(result parsers.ParserResult) []parsers.LineDecision {
+	multiParserResult, ok := result.(*parsers.MultiParserResult)
+	if !ok {
+		return nil
+	}
+	return multiParserResult.Decisions
+}
+
 func findKind(result parsers.ParserResult) (string, error) {
 	largestLines := -1
 	largestLinesParser := "unknown"
@@ -481,18 +638,46 @@ func findKind(result parsers.ParserResult) (string, error) {
 	return largestLinesParser, nil
 }
 
+// legacyKinds are the kinds that appraisals of a kind used to be stored with. Wallets were stored as
+// "view_contents" before they got their own kind.
+var legacyKinds = map[string][]string{
+	"wallet": {"view_contents"},
+}
+
+// KindMatches returns true if an appraisal of the given kind is wanted by a kind filter. An empty filter
+// matches every kind and a filter also matches the kinds its appraisals used to be stored with.
+func KindMatches(filter string, kind string) bool {
+	if filter == "" || filter == kind {
+		return true
+	}
+	for _, legacyKind := range legacyKinds[filter] {
+		if kind == legacyKind {
+			return true
+		}
+	}
+	return false
+}
+
 func parserResultToAppraisalItems(result parsers.ParserResult) []AppraisalItem {
+	return mergeAppraisalItems(parserResultToUnmergedItems(result))
+}
+
+// parserResultToUnmergedItems returns an item for everything in the result, before items with the same name
+// are combined
+func parserResultToUnmergedItems(result parsers.ParserResult) []AppraisalItem {
 	var items []AppraisalItem
 	switch r := result.(type) {
 	default:
 		log.Printf("unexpected type %T", r)
 	case *parsers.MultiParserResult:
 		for _, subResult := range r.Results {
-			items = append(items, parserResultToAppraisalItems(subResult)...)
+			items = append(items, parserResultToUnmergedItems(subResult)...)
 		}
 	case *parsers.AssetList:
 		for _, item := range r.Items {
-			items = append(items, AppraisalItem{Name: item.Name, Quantity: item.Quantity})
+			newItem := AppraisalItem{Name: item.Name, Quantity: item.Quantity}
+			newItem.Extra.Path = item.Path
+			items = append(items, newItem)
 		}
 	case *parsers.CargoScan:
 		for _, item := range r.Items {
@@ -522,17 +707,28 @@ func parserResultToAppraisalItems(result parsers.ParserResult) []AppraisalItem {
 			items = append(items, AppraisalItem{Name: item.Name, Quantity: 1})
 		}
 	case *parsers.EFT:
-		items = append(items, AppraisalItem{Name: r.Ship, Quantity: 1})
-		for _, item := range r.Items {
-			items = append(items, AppraisalItem{Name: item.Name, Quantity: item.Quantity})
+		items = append(items, shipFittingToAppraisalItems(eftToShipFitting(r))...)
+	case *parsers.EFTList:
+		for _, eft := range r.Fittings {
+			items = append(items, shipFittingToAppraisalItems(eftToShipFitting(eft))...)
 		}
 	case *parsers.Fitting:
 		for _, item := range r.Items {
 			items = append(items, AppraisalItem{Name: item.Name, Quantity: item.Quantity})
 		}
+	case *parsers.XMLFitting:
+		for _, fitting := range r.Fittings {
+			items = append(items, shipFittingToAppraisalItems(fitting)...)
+		}
+	case *parsers.DNAFitting:
+		for _, fitting := range r.Fittings {
+			items = append(items, shipFittingToAppraisalItems(fitting)...)
+		}
 	case *parsers.Industry:
 		for _, item := range r.Items {
-			items = append(items, AppraisalItem{Name: item.Name, Quantity: item.Quantity})
+			newItem := AppraisalItem{Name: item.Name, Quantity: item.Quantity}
+			newItem.Extra.JobRuns = item.Runs
+			items = append(items, newItem)
 		}
 	case *parsers.Killmail:
 		for _, item := range r.Dropped {
@@ -553,10 +749,45 @@ func parserResultToAppraisalItems(result parsers.ParserResult) []AppraisalItem {
 			newItem.Extra.Location = item.Location
 			items = append(items, newItem)
 		}
+	case *parsers.ESIKillmails:
+		for _, killmail := range r.Killmails {
+			for _, item := range killmail.Dropped {
+				newItem := AppraisalItem{
+					Name:     item.Name,
+					Quantity: item.Quantity,
+				}
+				newItem.Extra.Dropped = true
+				newItem.Extra.Location = item.Location
+				items = append(items, newItem)
+			}
+			for _, item := range killmail.Destroyed {
+				newItem := AppraisalItem{
+					Name:     item.Name,
+					Quantity: item.Quantity,
+				}
+				newItem.Extra.Destroyed = true
+				newItem.Extra.Location = item.Location
+				items = append(items, newItem)
+			}
+		}
+	case *parsers.Table:
+		for _, item := range r.Items {
+			items = append(items, AppraisalItem{Name: item.Name, Quantity: item.Quantity})
+		}
 	case *parsers.Listing:
 		for _, item := range r.Items {
 			items = append(items, AppraisalItem{Name: item.Name, Quantity: item.Quantity})
 		}
+	case *parsers.Multibuy:
+		for _, item := range r.Items {
+			items = append(items, AppraisalItem{Name: item.Name, Quantity: item.Quantity})
+		}
+	case *parsers.Quickbar:
+		for _, item := range r.Items {
+			newItem := AppraisalItem{Name: item.Name, Quantity: item.Quantity}
+			newItem.Extra.Path = item.Folder
+			items = append(items, newItem)
+		}
 	case *parsers.LootHistory:
 		for _, item := range r.Items {
 			newItem := AppraisalItem{
@@ -566,6 +797,19 @@ func parserResultToAppraisalItems(result parsers.ParserResult) []AppraisalItem {
 			newItem.Extra.PlayerName = item.PlayerName
 			items = append(items, newItem)
 		}
+	case *parsers.MarketOrders:
+		for _, item := range r.Items {
+			items = append(items, AppraisalItem{Name: item.Name, Quantity: item.Quantity})
+		}
+	case *parsers.MiningLedger:
+		for _, item := range r.Items {
+			newItem := AppraisalItem{
+				Name:     item.Name,
+				Quantity: item.Quantity,
+			}
+			newItem.Extra.Volume = item.Volume
+			items = append(items, newItem)
+		}
 	case *parsers.PI:
 		for _, item := range r.Items {
 			newItem := AppraisalItem{
@@ -592,15 +836,12 @@ func parserResultToAppraisalItems(result parsers.ParserResult) []AppraisalItem {
 				Quantity: item.Quantity,
 			}
 			newItem.Extra.Location = item.Location
+			newItem.Extra.Path = item.Path
 			items = append(items, newItem)
 		}
 	case *parsers.Wallet:
 		for _, item := range r.ItemizedTransactions {
-			items = append(items,
-				AppraisalItem{
-					Name:     item.Name,
-					Quantity: item.Quantity,
-				})
+			items = append(items, AppraisalItem{Name: item.Name, Quantity: item.Quantity})
 		}
 	case *parsers.HeuristicResult:
 		for _, item := range r.Items {
@@ -608,18 +849,203 @@ func parserResultToAppraisalItems(result parsers.ParserResult) []AppraisalItem {
 		}
 	}
 
+	return items
+}
+
+// parserResultToGroups returns the groups of items for results that contain more structure than a flat list
+func parserResultToGroups(result parsers.ParserResult) []ItemGroup {
+	var groups []ItemGroup
+	switch r := result.(type) {
+	case *parsers.MultiParserResult:
+		for _, subResult := range r.Results {
+			groups = append(groups, parserResultToGroups(subResult)...)
+		}
+	case *parsers.EFT:
+		groups = append(groups, shipFittingToGroup(eftToShipFitting(r)))
+	case *parsers.EFTList:
+		for _, eft := range r.Fittings {
+			groups = append(groups, shipFittingToGroup(eftToShipFitting(eft)))
+		}
+	case *parsers.XMLFitting:
+		for _, fitting := range r.Fittings {
+			groups = append(groups, shipFittingToGroup(fitting))
+		}
+	case *parsers.DNAFitting:
+		for _, fitting := range r.Fittings {
+			groups = append(groups, shipFittingToGroup(fitting))
+		}
+	case *parsers.Fitting:
+		for _, section := range r.Sections {
+			groups = append(groups, shipFittingToGroup(parsers.ShipFitting{Name: section.Name, Items: section.Items}))
+		}
+	case *parsers.AssetList:
+		items := parserResultToUnmergedItems(r)
+		names := make([]string, len(r.Items))
+		for i, item := range r.Items {
+			names[i] = item.Category
+		}
+		groups = append(groups, groupItemsByName(names, items, "Other")...)
+		groups = append(groups, groupItemsByContainer(items)...)
+	case *parsers.Quickbar:
+		groups = append(groups, groupItemsByContainer(parserResultToUnmergedItems(r))...)
+	case *parsers.Industry:
+		items := parserResultToUnmergedItems(r)
+		names := make([]string, len(items))
+		for i, item := range items {
+			names[i] = "Materials"
+			if item.Extra.JobRuns != 0 {
+				names[i] = "Job output"
+			}
+		}
+		groups = append(groups, groupItemsByName(names, items, "")...)
+	case *parsers.LootHistory:
+		groups = append(groups, groupItemsByPlayer(parserResultToUnmergedItems(r))...)
+	case *parsers.Killmail, *parsers.ESIKillmails, *parsers.ViewContents:
+		emptyName := "Fitted"
+		if _, ok := r.(*parsers.ViewContents); ok {
+			emptyName = "Contents"
+		}
+		items := parserResultToUnmergedItems(r)
+		names := make([]string, len(items))
+		for i, item := range items {
+			names[i] = item.Extra.Location
+		}
+		groups = append(groups, groupItemsByName(names, items, emptyName)...)
+		groups = append(groups, groupItemsByContainer(items)...)
+	}
+	return groups
+}
+
+// groupItemsByContainer returns a group for every container with everything inside it, including what is in
+// containers nested inside of it. The container itself is not part of its group.
+func groupItemsByContainer(items []AppraisalItem) []ItemGroup {
+	var groups []ItemGroup
+	groupIndex := make(map[string]int)
+	for _, item := range items {
+		if item.Extra.Path == "" {
+			continue
+		}
+
+		containers := strings.Split(item.Extra.Path, parsers.ContainerPathSeparator)
+		for i := range containers {
+			path := strings.Join(containers[:i+1], parsers.ContainerPathSeparator)
+			idx, ok := groupIndex[path]
+			if !ok {
+				idx = len(groups)
+				groupIndex[path] = idx
+				groups = append(groups, ItemGroup{Name: path})
+			}
+			groups[idx].Items = append(groups[idx].Items, item)
+		}
+	}
+
+	for i := range groups {
+		groups[i].Items = mergeAppraisalItems(groups[i].Items)
+	}
+	return groups
+}
+
+// groupItemsByPlayer returns a group with the loot of every player, in the order in which the players first show
+// up. Unlike other groups these are returned even if there is only one player, since they are what loot is split by.
+func groupItemsByPlayer(items []AppraisalItem) []ItemGroup {
+	var groups []ItemGroup
+	groupIndex := make(map[string]int)
+	for _, item := range items {
+		if item.Extra.PlayerName == "" {
+			continue
+		}
+
+		key := strings.ToUpper(item.Extra.PlayerName)
+		idx, ok := groupIndex[key]
+		if !ok {
+			idx = len(groups)
+			groupIndex[key] = idx
+			groups = append(groups, ItemGroup{Name: item.Extra.PlayerName, Player: item.Extra.PlayerName})
+		}
+		groups[idx].Items = append(groups[idx].Items, item)
+	}
+
+	for i := range groups {
+		groups[i].Items = mergeAppraisalItems(groups[i].Items)
+	}
+	return groups
+}
+
+// groupItemsByName puts items into one group per name, in the order in which the names first show up. Items
+// without a name go into a group called emptyName. Nothing is returned if all items end up in the same group,
+// since that group would be the same as the whole appraisal.
+func groupItemsByName(names []string, items []AppraisalItem, emptyName string) []ItemGroup {
+	var groups []ItemGroup
+	groupIndex := make(map[string]int)
+	for i, item := range items {
+		name := names[i]
+		if name == "" {
+			name = emptyName
+		}
+
+		idx, ok := groupIndex[name]
+		if !ok {
+			idx = len(groups)
+			groupIndex[name] = idx
+			groups = append(groups, ItemGroup{Name: name})
+		}
+		groups[idx].Items = append(groups[idx].Items, item)
+	}
+
+	if len(groups) < 2 {
+		return nil
+	}
+
+	for i := range groups {
+		groups[i].Items = mergeAppraisalItems(groups[i].Items)
+	}
+	return groups
+}
+
+func eftToShipFitting(eft *parsers.EFT) parsers.ShipFitting {
+	return parsers.ShipFitting{Name: eft.FittingName, Ship: eft.Ship, Items: eft.Items}
+}
+
+func shipFittingToGroup(fitting parsers.ShipFitting) ItemGroup {
+	name := fitting.Name
+	if name == "" {
+		name = fitting.Ship
+	}
+	return ItemGroup{
+		Name:  name,
+		Ship:  fitting.Ship,
+		Items: mergeAppraisalItems(shipFittingToAppraisalItems(fitting)),
+	}
+}
+
+func shipFittingToAppraisalItems(fitting parsers.ShipFitting) []AppraisalItem {
+	var items []AppraisalItem
+	if fitting.Ship != "" {
+		items = append(items, AppraisalItem{Name: fitting.Ship, Quantity: 1})
+	}
+	for _, item := range fitting.Items {
+		items = append(items, AppraisalItem{Name: item.Name, Quantity: item.Quantity})
+	}
+	return items
+}
+
+// mergeAppraisalItems combines items with the same name, adding up their quantities and volumes
+func mergeAppraisalItems(items []AppraisalItem) []AppraisalItem {
 	itemMap := make(map[string]AppraisalItem)
 	quantityMap := make(map[string]int64)
+	volumeMap := make(map[string]float64)
 	for _, item := range items {
 		item.Name = strings.Trim(item.Name, " \t")
 		key := strings.ToUpper(item.Name)
 		itemMap[key] = item
 		quantityMap[key] += item.Quantity
+		volumeMap[key] += item.Extra.Volume
 	}
 
 	returnItems := make([]AppraisalItem, 0, len(itemMap))
 	for key, item := range itemMap {
 		item.Quantity = quantityMap[key]
+		item.Extra.Volume = volumeMap[key]
 		returnItems = append(returnItems, item)
 	}
 
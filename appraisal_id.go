@@ -1,6 +1,11 @@
 package evepraisal
 
-import "github.com/martinlindhe/base36"
+import (
+	"regexp"
+	"strings"
+
+	"github.com/martinlindhe/base36"
+)
 
 func AppraisalIDToUint64(appraisalID string) uint64 {
 	return base36.Decode(appraisalID)
@@ -9,3 +14,15 @@ func AppraisalIDToUint64(appraisalID string) uint64 {
 func Uint64ToAppraisalID(aID uint64) string {
 	return base36.Encode(aID)
 }
+
+// Longer IDs don't fit into the 64 bit IDs in the database, or can't be decoded exactly
+var reAppraisalID = regexp.MustCompile(`^[0-9a-zA-Z]{1,11}$`)
+
+// ValidAppraisalID returns true if the appraisal ID is made of base36 digits and decodes back to itself. IDs with
+// leading zeros are rejected since they would be the same appraisal as the ID without them.
+func ValidAppraisalID(appraisalID string) bool {
+	if !reAppraisalID.MatchString(appraisalID) {
+		return false
+	}
+	return strings.EqualFold(Uint64ToAppraisalID(AppraisalIDToUint64(appraisalID)), appraisalID)
+}
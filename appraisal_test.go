@@ -0,0 +1,105 @@
+package evepraisal
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/evepraisal/go-evepraisal/typedb"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResolvePinnedTypes(t *testing.T) {
+	current := newFakeTypeDB(typedb.EveType{ID: 34, Name: "Tritanium", Volume: 0.01})
+	current.version = "new"
+	old := newFakeTypeDB(
+		typedb.EveType{ID: 34, Name: "Tritanium", Volume: 0.01},
+		typedb.EveType{ID: 100, Name: "Retired Frigate", Volume: 2500},
+		typedb.EveType{ID: 101, Name: "Retired Module", Volume: 5},
+	)
+	old.version = "old"
+
+	archive := typedb.NewArchive(2, 0)
+	archive.Add(old)
+	archive.Add(current)
+	app := &App{TypeDB: current, TypeDBArchive: archive}
+
+	newAppraisal := func(version string) *Appraisal {
+		appraisal := &Appraisal{SDEVersion: version}
+		appraisal.Original.Items = []AppraisalItem{
+			{Name: "Tritanium", TypeID: 34},
+			{Name: "Retired Frigate"},
+			{Name: "Something", TypeID: 101, TypeVolume: 10},
+			{Name: "Unknown Thing"},
+		}
+		return appraisal
+	}
+
+	appraisal := newAppraisal("old")
+	app.ResolvePinnedTypes(appraisal)
+	items := appraisal.Original.Items
+
+	assert.False(t, items[0].Extra.Retired, "types the current typedb knows are left alone")
+
+	assert.True(t, items[1].Extra.Retired)
+	assert.Equal(t, int64(100), items[1].TypeID)
+	assert.Equal(t, "Retired Frigate", items[1].TypeName)
+	assert.Equal(t, 2500.0, items[1].TypeVolume)
+
+	assert.True(t, items[2].Extra.Retired)
+	assert.Equal(t, "Retired Module", items[2].TypeName)
+	assert.Equal(t, 10.0, items[2].TypeVolume, "a volume from the paste is kept")
+
+	assert.False(t, items[3].Extra.Retired)
+	assert.Equal(t, int64(0), items[3].TypeID)
+
+	for _, version := range []string{"", "new", "missing"} {
+		appraisal := newAppraisal(version)
+		app.ResolvePinnedTypes(appraisal)
+		for _, item := range appraisal.Original.Items {
+			assert.False(t, item.Extra.Retired, version)
+		}
+		assert.Equal(t, int64(0), appraisal.Original.Items[1].TypeID, version)
+	}
+}
+
+func TestKindMatches(rt *testing.T) {
+	cases := []struct {
+		filter  string
+		kind    string
+		matches bool
+	}{
+		{"", "listing", true},
+		{"listing", "listing", true},
+		{"listing", "eft", false},
+		{"wallet", "wallet", true},
+		{"wallet", "view_contents", true},
+		{"view_contents", "wallet", false},
+		{"view_contents", "view_contents", true},
+	}
+
+	for _, c := range cases {
+		rt.Run(c.filter+"/"+c.kind, func(t *testing.T) {
+			assert.Equal(t, c.matches, KindMatches(c.filter, c.kind))
+		})
+	}
+}
+
+func TestMergeAppraisalItems(t *testing.T) {
+	items := []AppraisalItem{
+		{Name: "Tritanium", Quantity: 10},
+		{Name: " tritanium\t", Quantity: 5},
+		{Name: "Pyerite", Quantity: 1},
+	}
+	items[0].Extra.Path = "Box"
+	items[0].Extra.Volume = 0.1
+	items[1].Extra.PlayerName = "Alice"
+	items[1].Extra.Volume = 0.05
+
+	merged := mergeAppraisalItems(items)
+	sort.Slice(merged, func(i, j int) bool { return merged[i].Name < merged[j].Name })
+	if assert.Len(t, merged, 2, "items are merged by name only") {
+		assert.Equal(t, "Pyerite", merged[0].Name)
+		assert.Equal(t, int64(15), merged[1].Quantity)
+		assert.InDelta(t, 0.15, merged[1].Extra.Volume, 0.0001)
+	}
+}
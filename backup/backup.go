@@ -0,0 +1,243 @@
+package backup
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	timestampFormat = "20060102T150405Z"
+	fileSuffix      = ".bolt.gz"
+)
+
+// Snapshotter is implemented by databases that can write a consistent copy of themselves while in use
+type Snapshotter interface {
+	Snapshot(w io.Writer) (int64, error)
+}
+
+// SizedSnapshotter is implemented by databases that know the size of a snapshot before writing it, so it
+// can be streamed with a Content-Length
+type SizedSnapshotter interface {
+	SizedSnapshot(start func(size int64) io.Writer) (int64, error)
+}
+
+// Backup describes a single snapshot file on disk
+type Backup struct {
+	Name     string    `json:"name"`
+	Filename string    `json:"filename"`
+	Size     int64     `json:"size"`
+	Created  time.Time `json:"created"`
+}
+
+// Manager periodically writes gzipped snapshots of each source to a directory, keeping the last few per source
+type Manager struct {
+	dir      string
+	interval time.Duration
+	keep     int
+	sources  func() map[string]Snapshotter
+
+	mu   sync.Mutex
+	stop chan bool
+	wg   *sync.WaitGroup
+}
+
+// NewManager returns a new backup manager. Sources is called on every run so databases that get swapped
+// out (like the typedb) are always current. An interval of zero disables scheduled backups.
+func NewManager(dir string, interval time.Duration, keep int, sources func() map[string]Snapshotter) (*Manager, error) {
+	err := os.MkdirAll(dir, 0700)
+	if err != nil {
+		return nil, err
+	}
+
+	m := &Manager{
+		dir:      dir,
+		interval: interval,
+		keep:     keep,
+		sources:  sources,
+
+		stop: make(chan bool),
+		wg:   &sync.WaitGroup{},
+	}
+
+	if interval > 0 {
+		m.wg.Add(1)
+		go func() {
+			defer m.wg.Done()
+			for {
+				select {
+				case <-time.After(m.interval):
+				case <-m.stop:
+					return
+				}
+
+				_, err := m.BackupAll()
+				if err != nil {
+					log.Printf("WARNING: Scheduled backup failed: %s", err)
+				}
+			}
+		}()
+	}
+
+	return m, nil
+}
+
+// BackupAll snapshots every source and rotates out old snapshots
+func (m *Manager) BackupAll() ([]Backup, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Now().UTC()
+	backups := make([]Backup, 0)
+	for name, source := range m.sources() {
+		if source == nil {
+			continue
+		}
+
+		log.Printf("Start backing up %s", name)
+		backup, err := m.backup(name, source, now)
+		if err != nil {
+			return backups, fmt.Errorf("backup %s: %s", name, err)
+		}
+		backups = append(backups, backup)
+		log.Printf("Done backing up %s to %s (%d bytes)", name, backup.Filename, backup.Size)
+
+		err = m.rotate(name)
+		if err != nil {
+			return backups, fmt.Errorf("rotate %s: %s", name, err)
+		}
+	}
+	return backups, nil
+}
+
+func (m *Manager) backup(name string, source Snapshotter, now time.Time) (Backup, error) {
+	backup := Backup{
+		Name:     name,
+		Filename: filepath.Join(m.dir, name+"-"+now.Format(timestampFormat)+fileSuffix),
+		Created:  now,
+	}
+
+	f, err := ioutil.TempFile(m.dir, name+"-")
+	if err != nil {
+		return backup, err
+	}
+	defer os.Remove(f.Name())
+	defer f.Close()
+
+	gz := gzip.NewWriter(f)
+	_, err = source.Snapshot(gz)
+	if err != nil {
+		return backup, err
+	}
+
+	err = gz.Close()
+	if err != nil {
+		return backup, err
+	}
+
+	err = f.Sync()
+	if err != nil {
+		return backup, err
+	}
+
+	stat, err := f.Stat()
+	if err != nil {
+		return backup, err
+	}
+	backup.Size = stat.Size()
+
+	err = f.Close()
+	if err != nil {
+		return backup, err
+	}
+
+	return backup, os.Rename(f.Name(), backup.Filename)
+}
+
+func (m *Manager) rotate(name string) error {
+	if m.keep <= 0 {
+		return nil
+	}
+
+	backups, err := m.List()
+	if err != nil {
+		return err
+	}
+
+	count := 0
+	for _, backup := range backups {
+		if backup.Name != name {
+			continue
+		}
+
+		count++
+		if count > m.keep {
+			log.Printf("Removing old backup %s", backup.Filename)
+			err = os.Remove(backup.Filename)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// List returns all snapshots in the backup directory, newest first
+func (m *Manager) List() ([]Backup, error) {
+	files, err := ioutil.ReadDir(m.dir)
+	if err != nil {
+		return nil, err
+	}
+
+	backups := make([]Backup, 0)
+	for _, file := range files {
+		backup, ok := ParseFilename(file.Name())
+		if !ok {
+			continue
+		}
+		backup.Filename = filepath.Join(m.dir, file.Name())
+		backup.Size = file.Size()
+		backups = append(backups, backup)
+	}
+
+	sort.Slice(backups, func(i, j int) bool {
+		return backups[i].Created.After(backups[j].Created)
+	})
+	return backups, nil
+}
+
+// Close stops scheduled backups
+func (m *Manager) Close() error {
+	close(m.stop)
+	m.wg.Wait()
+	return nil
+}
+
+// ParseFilename returns the database name and creation time encoded in a snapshot filename
+func ParseFilename(filename string) (Backup, bool) {
+	base := filepath.Base(filename)
+	if !strings.HasSuffix(base, fileSuffix) {
+		return Backup{}, false
+	}
+
+	base = strings.TrimSuffix(base, fileSuffix)
+	idx := strings.LastIndex(base, "-")
+	if idx <= 0 {
+		return Backup{}, false
+	}
+
+	created, err := time.Parse(timestampFormat, base[idx+1:])
+	if err != nil {
+		return Backup{}, false
+	}
+
+	return Backup{Name: base[:idx], Filename: filename, Created: created}, true
+}
@@ -0,0 +1,202 @@
+package backup
+
+import (
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/boltdb/bolt"
+	"github.com/stretchr/testify/assert"
+)
+
+// boltSource snapshots a bolt database the same way the real databases do
+type boltSource struct {
+	db *bolt.DB
+}
+
+func (s boltSource) Snapshot(w io.Writer) (int64, error) {
+	var n int64
+	err := s.db.View(func(tx *bolt.Tx) error {
+		var err error
+		n, err = tx.WriteTo(w)
+		return err
+	})
+	return n, err
+}
+
+func newTestDir(t *testing.T) (string, func()) {
+	dir, err := ioutil.TempDir("", "backup")
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	return dir, func() { os.RemoveAll(dir) }
+}
+
+func newTestBolt(t *testing.T, filename string, value string) *bolt.DB {
+	db, err := bolt.Open(filename, 0600, &bolt.Options{Timeout: 1 * time.Second})
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	err = db.Update(func(tx *bolt.Tx) error {
+		b, err := tx.CreateBucketIfNotExists([]byte("test"))
+		if err != nil {
+			return err
+		}
+		return b.Put([]byte("key"), []byte(value))
+	})
+	assert.NoError(t, err)
+	return db
+}
+
+func readTestBolt(t *testing.T, filename string) string {
+	db, err := bolt.Open(filename, 0600, &bolt.Options{Timeout: 1 * time.Second, ReadOnly: true})
+	if !assert.NoError(t, err) {
+		return ""
+	}
+	defer db.Close()
+
+	var value string
+	db.View(func(tx *bolt.Tx) error {
+		b := tx.Bucket([]byte("test"))
+		if b != nil {
+			value = string(b.Get([]byte("key")))
+		}
+		return nil
+	})
+	return value
+}
+
+func TestParseFilename(rt *testing.T) {
+	cases := []struct {
+		filename string
+		ok       bool
+		name     string
+		created  time.Time
+	}{
+		{"appraisals-20170102T030405Z.bolt.gz", true, "appraisals", time.Date(2017, 1, 2, 3, 4, 5, 0, time.UTC)},
+		{"/backups/http-cache-20170102T030405Z.bolt.gz", true, "http-cache", time.Date(2017, 1, 2, 3, 4, 5, 0, time.UTC)},
+		{"appraisals-20170102T030405Z.bolt", false, "", time.Time{}},
+		{"appraisals-2017.bolt.gz", false, "", time.Time{}},
+		{"-20170102T030405Z.bolt.gz", false, "", time.Time{}},
+		{"appraisals123.bolt.gz", false, "", time.Time{}},
+	}
+
+	for _, c := range cases {
+		rt.Run(c.filename, func(t *testing.T) {
+			backup, ok := ParseFilename(c.filename)
+			assert.Equal(t, c.ok, ok)
+			if !ok {
+				return
+			}
+			assert.Equal(t, c.name, backup.Name)
+			assert.Equal(t, c.filename, backup.Filename)
+			assert.True(t, c.created.Equal(backup.Created), backup.Created.String())
+		})
+	}
+}
+
+func TestRotate(t *testing.T) {
+	dir, cleanup := newTestDir(t)
+	defer cleanup()
+
+	db := newTestBolt(t, filepath.Join(dir, "source"), "value")
+	defer db.Close()
+
+	m, err := NewManager(filepath.Join(dir, "backups"), 0, 2, nil)
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer m.Close()
+
+	start := time.Date(2017, 1, 2, 3, 4, 5, 0, time.UTC)
+	for i := 0; i < 4; i++ {
+		for _, name := range []string{"appraisals", "prices"} {
+			_, err = m.backup(name, boltSource{db}, start.Add(time.Duration(i)*time.Hour))
+			assert.NoError(t, err)
+		}
+	}
+	assert.NoError(t, m.rotate("appraisals"))
+
+	backups, err := m.List()
+	assert.NoError(t, err)
+
+	kept := make(map[string][]time.Time)
+	for _, backup := range backups {
+		kept[backup.Name] = append(kept[backup.Name], backup.Created)
+		assert.True(t, backup.Size > 0)
+	}
+	assert.Equal(t, []time.Time{start.Add(3 * time.Hour), start.Add(2 * time.Hour)}, kept["appraisals"])
+	assert.Len(t, kept["prices"], 4, "only the rotated database should lose snapshots")
+}
+
+func TestBackupRestore(t *testing.T) {
+	dir, cleanup := newTestDir(t)
+	defer cleanup()
+
+	db := newTestBolt(t, filepath.Join(dir, "source"), "from snapshot")
+	m, err := NewManager(filepath.Join(dir, "backups"), 0, 1, func() map[string]Snapshotter {
+		return map[string]Snapshotter{"appraisals": boltSource{db}}
+	})
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer m.Close()
+
+	backups, err := m.BackupAll()
+	db.Close()
+	if !assert.NoError(t, err) || !assert.Len(t, backups, 1) {
+		return
+	}
+	assert.NoError(t, Verify(filepath.Join(dir, "source")))
+
+	target := filepath.Join(dir, "appraisals")
+	current := newTestBolt(t, target, "current")
+	current.Close()
+
+	assert.NoError(t, Restore(backups[0].Filename, target))
+	assert.Equal(t, "from snapshot", readTestBolt(t, target))
+	assert.Equal(t, "current", readTestBolt(t, target+".pre-restore"))
+	_, err = os.Stat(target + ".restore")
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestRestoreInvalidSnapshot(t *testing.T) {
+	dir, cleanup := newTestDir(t)
+	defer cleanup()
+
+	target := filepath.Join(dir, "appraisals")
+	current := newTestBolt(t, target, "current")
+	current.Close()
+
+	snapshot := filepath.Join(dir, "appraisals-20170102T030405Z.bolt.gz")
+	assert.NoError(t, ioutil.WriteFile(snapshot, []byte("not gzip"), 0600))
+
+	assert.Error(t, Restore(snapshot, target))
+	assert.Equal(t, "current", readTestBolt(t, target))
+	_, err := os.Stat(target + ".restore")
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestRestoreInUse(t *testing.T) {
+	dir, cleanup := newTestDir(t)
+	defer cleanup()
+
+	db := newTestBolt(t, filepath.Join(dir, "source"), "from snapshot")
+	m, err := NewManager(filepath.Join(dir, "backups"), 0, 1, nil)
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer m.Close()
+	backup, err := m.backup("appraisals", boltSource{db}, time.Now().UTC())
+	db.Close()
+	assert.NoError(t, err)
+
+	target := filepath.Join(dir, "appraisals")
+	current := newTestBolt(t, target, "current")
+	defer current.Close()
+
+	assert.Error(t, Restore(backup.Filename, target))
+}
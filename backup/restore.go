@@ -0,0 +1,101 @@
+package backup
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/boltdb/bolt"
+)
+
+// Verify opens a bolt database read-only and runs a full consistency check on it
+func Verify(filename string) error {
+	db, err := bolt.Open(filename, 0600, &bolt.Options{Timeout: 1 * time.Second, ReadOnly: true})
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	return db.View(func(tx *bolt.Tx) error {
+		errs := make([]string, 0)
+		for err := range tx.Check() {
+			errs = append(errs, err.Error())
+		}
+		if len(errs) > 0 {
+			return fmt.Errorf("database check failed: %s", strings.Join(errs, "; "))
+		}
+		return nil
+	})
+}
+
+// Restore decompresses and verifies a snapshot, then swaps it in place of target. The previous database
+// is kept next to the target with a ".pre-restore" suffix. The target must not be in use.
+func Restore(snapshot string, target string) error {
+	tmpFilename := target + ".restore"
+	err := decompress(snapshot, tmpFilename)
+	if err != nil {
+		os.Remove(tmpFilename)
+		return fmt.Errorf("unable to decompress snapshot: %s", err)
+	}
+
+	err = Verify(tmpFilename)
+	if err != nil {
+		os.Remove(tmpFilename)
+		return err
+	}
+
+	if _, err := os.Stat(target); err == nil {
+		// bolt holds an exclusive lock while the database is open, so this fails if the server is running
+		db, err := bolt.Open(target, 0600, &bolt.Options{Timeout: 1 * time.Second})
+		if err != nil {
+			os.Remove(tmpFilename)
+			return fmt.Errorf("unable to lock %s, is it still in use? (%s)", target, err)
+		}
+		db.Close()
+
+		err = os.Rename(target, target+".pre-restore")
+		if err != nil {
+			os.Remove(tmpFilename)
+			return err
+		}
+	} else if !os.IsNotExist(err) {
+		os.Remove(tmpFilename)
+		return err
+	}
+
+	return os.Rename(tmpFilename, target)
+}
+
+func decompress(src string, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	gz, err := gzip.NewReader(in)
+	if err != nil {
+		return err
+	}
+	defer gz.Close()
+
+	out, err := os.OpenFile(dst, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	_, err = io.Copy(out, gz)
+	if err != nil {
+		return err
+	}
+
+	err = out.Sync()
+	if err != nil {
+		return err
+	}
+	return out.Close()
+}
@@ -82,52 +82,167 @@ func NewAppraisalDB(filename string) (evepraisal.AppraisalDB, error) {
 func (db *AppraisalDB) PutNewAppraisal(appraisal *evepraisal.Appraisal) error {
 	var dbID []byte
 	err := db.DB.Update(func(tx *bolt.Tx) error {
-		byIDBucket := tx.Bucket([]byte("appraisals"))
 		var err error
-		if appraisal.ID == "" {
-			id, err := byIDBucket.NextSequence()
+		dbID, err = putAppraisal(tx, appraisal)
+		return err
+	})
+	if err != nil {
+		go db.setLastUsedTime(dbID)
+	}
+	return err
+}
+
+// PutAppraisals stores a batch of existing appraisals inside of a single transaction. Appraisals that
+// already exist (or are repeated in the batch) are skipped with AppraisalExists and ones with an ID that can't be
+// stored with InvalidAppraisalID. Every record is checked before anything of it is written, so a skipped record
+// leaves nothing behind. The returned slice has an entry for each given appraisal; the second return value is only
+// set when the whole batch failed, in which case nothing of the batch is stored.
+func (db *AppraisalDB) PutAppraisals(appraisals []*evepraisal.Appraisal) ([]error, error) {
+	errs := make([]error, len(appraisals))
+	err := db.DB.Update(func(tx *bolt.Tx) error {
+		byIDBucket := tx.Bucket([]byte("appraisals"))
+		lastUsedBucket := tx.Bucket([]byte("appraisals-last-used"))
+
+		now := make([]byte, 8)
+		binary.BigEndian.PutUint64(now, uint64(time.Now().Unix()))
+
+		seen := make(map[string]bool, len(appraisals))
+		for i, appraisal := range appraisals {
+			var dbID []byte
+			if appraisal.ID != "" {
+				if !evepraisal.ValidAppraisalID(appraisal.ID) {
+					errs[i] = evepraisal.InvalidAppraisalID
+					continue
+				}
+
+				var err error
+				dbID, err = EncodeDBID(appraisal.ID)
+				if err != nil {
+					errs[i] = err
+					continue
+				}
+
+				if seen[string(dbID)] || byIDBucket.Get(dbID) != nil {
+					errs[i] = evepraisal.AppraisalExists
+					continue
+				}
+				seen[string(dbID)] = true
+			}
+
+			if appraisal.User != nil && len(userKey(appraisal, make([]byte, 8))) > bolt.MaxKeySize {
+				errs[i] = fmt.Errorf("character owner hash is too long")
+				continue
+			}
+
+			if dbID == nil {
+				var err error
+				dbID, err = nextAppraisalID(byIDBucket, appraisal)
+				if err != nil {
+					return err
+				}
+			}
+
+			value, err := encodeAppraisal(appraisal)
 			if err != nil {
-				return err
+				errs[i] = err
+				continue
+			}
+
+			// Make sure that new appraisals never collide with imported ones
+			id := binary.BigEndian.Uint64(dbID)
+			if id > byIDBucket.Sequence() {
+				err = byIDBucket.SetSequence(id)
+				if err != nil {
+					return err
+				}
 			}
 
-			dbID = EncodeDBIDFromUint64(id)
-			appraisal.ID, err = DecodeDBID(dbID)
+			// The record is valid, so failing to write it means the database is in trouble and the batch is rolled back
+			err = writeAppraisal(tx, dbID, appraisal, value)
 			if err != nil {
 				return err
 			}
-		} else {
-			dbID, err = EncodeDBID(appraisal.ID)
+
+			err = lastUsedBucket.Put(dbID, now)
 			if err != nil {
 				return err
 			}
 		}
+		return nil
+	})
+	return errs, err
+}
 
-		if appraisal.User != nil {
-			appraisal.OwnerID = appraisal.User.CharacterID
-		}
-
-		var buf bytes.Buffer
-		encoder := gob.NewEncoder(&buf)
-		err = encoder.Encode(appraisal)
+func putAppraisal(tx *bolt.Tx, appraisal *evepraisal.Appraisal) ([]byte, error) {
+	byIDBucket := tx.Bucket([]byte("appraisals"))
+	var (
+		dbID []byte
+		err  error
+	)
+	if appraisal.ID == "" {
+		dbID, err = nextAppraisalID(byIDBucket, appraisal)
 		if err != nil {
-			return err
+			return nil, err
 		}
-
-		err = byIDBucket.Put(dbID, snappy.Encode(nil, buf.Bytes()))
+	} else {
+		dbID, err = EncodeDBID(appraisal.ID)
 		if err != nil {
-			return err
+			return nil, err
 		}
+	}
 
-		if appraisal.User != nil {
-			byUserBucket := tx.Bucket([]byte("appraisals-by-user"))
-			return byUserBucket.Put(append([]byte(fmt.Sprintf("%s:", appraisal.User.CharacterOwnerHash)), dbID...), dbID)
-		}
-		return nil
-	})
+	value, err := encodeAppraisal(appraisal)
 	if err != nil {
-		go db.setLastUsedTime(dbID)
+		return dbID, err
 	}
-	return err
+	return dbID, writeAppraisal(tx, dbID, appraisal, value)
+}
+
+// nextAppraisalID gives the appraisal a new ID from the sequence of the bucket
+func nextAppraisalID(byIDBucket *bolt.Bucket, appraisal *evepraisal.Appraisal) ([]byte, error) {
+	id, err := byIDBucket.NextSequence()
+	if err != nil {
+		return nil, err
+	}
+
+	dbID := EncodeDBIDFromUint64(id)
+	appraisal.ID, err = DecodeDBID(dbID)
+	if err != nil {
+		return nil, err
+	}
+	return dbID, nil
+}
+
+// encodeAppraisal returns the appraisal the way it is stored
+func encodeAppraisal(appraisal *evepraisal.Appraisal) ([]byte, error) {
+	if appraisal.User != nil {
+		appraisal.OwnerID = appraisal.User.CharacterID
+	}
+
+	var buf bytes.Buffer
+	encoder := gob.NewEncoder(&buf)
+	err := encoder.Encode(appraisal)
+	if err != nil {
+		return nil, err
+	}
+	return snappy.Encode(nil, buf.Bytes()), nil
+}
+
+// writeAppraisal stores an encoded appraisal along with the index of the appraisals of its user
+func writeAppraisal(tx *bolt.Tx, dbID []byte, appraisal *evepraisal.Appraisal, value []byte) error {
+	err := tx.Bucket([]byte("appraisals")).Put(dbID, value)
+	if err != nil {
+		return err
+	}
+
+	if appraisal.User != nil {
+		return tx.Bucket([]byte("appraisals-by-user")).Put(userKey(appraisal, dbID), dbID)
+	}
+	return nil
+}
+
+func userKey(appraisal *evepraisal.Appraisal, dbID []byte) []byte {
+	return append([]byte(fmt.Sprintf("%s:", appraisal.User.CharacterOwnerHash)), dbID...)
 }
 
 func (db *AppraisalDB) GetAppraisal(appraisalID string) (*evepraisal.Appraisal, error) {
@@ -255,7 +370,7 @@ func (db *AppraisalDB) LatestAppraisals(reqCount int, kind string) ([]evepraisal
 				continue
 			}
 
-			if kind != "" && appraisal.Kind != kind {
+			if !evepraisal.KindMatches(kind, appraisal.Kind) {
 				continue
 			}
 
@@ -310,7 +425,7 @@ func (db *AppraisalDB) LatestAppraisalsByUser(user evepraisal.User, reqCount int
 				return err
 			}
 
-			if kind != "" && appraisal.Kind != kind {
+			if !evepraisal.KindMatches(kind, appraisal.Kind) {
 				continue
 			}
 
@@ -331,6 +446,41 @@ func (db *AppraisalDB) LatestAppraisalsByUser(user evepraisal.User, reqCount int
 	return appraisals, err
 }
 
+// ForEachAppraisal calls fn for every appraisal, oldest first, that was created at or after since
+// and matches the given kind (see evepraisal.KindMatches)
+func (db *AppraisalDB) ForEachAppraisal(since time.Time, kind string, fn func(appraisal *evepraisal.Appraisal) error) error {
+	return db.DB.View(func(tx *bolt.Tx) error {
+		c := tx.Bucket([]byte("appraisals")).Cursor()
+		for key, val := c.First(); key != nil; key, val = c.Next() {
+			buf, err := snappy.Decode(nil, val)
+			if err != nil {
+				return fmt.Errorf("Error when decoding: %s", err)
+			}
+
+			appraisal := &evepraisal.Appraisal{}
+			decoder := gob.NewDecoder(bytes.NewBuffer(buf))
+			err = decoder.Decode(appraisal)
+			if err != nil {
+				return err
+			}
+
+			if appraisal.Created < since.Unix() {
+				continue
+			}
+
+			if !evepraisal.KindMatches(kind, appraisal.Kind) {
+				continue
+			}
+
+			err = fn(appraisal)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
 func (db *AppraisalDB) TotalAppraisals() (int64, error) {
 	var total int64
 	err := db.DB.View(func(tx *bolt.Tx) error {
@@ -0,0 +1,148 @@
+package bolt
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/boltdb/bolt"
+	"github.com/evepraisal/go-evepraisal"
+	"github.com/stretchr/testify/assert"
+)
+
+// newTestAppraisalDB returns an appraisal database in a temporary directory, along with a function that closes
+// and removes it
+func newTestAppraisalDB(t *testing.T) (*AppraisalDB, func()) {
+	dir, err := ioutil.TempDir("", "appraisals")
+	assert.NoError(t, err)
+	db, err := NewAppraisalDB(filepath.Join(dir, "appraisals"))
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	return db.(*AppraisalDB), func() {
+		db.Close()
+		os.RemoveAll(dir)
+	}
+}
+
+func TestValidAppraisalID(rt *testing.T) {
+	cases := []struct {
+		id    string
+		valid bool
+	}{
+		{"abc12", true},
+		{"ABC12", true},
+		{"zzzzzzzzzzz", true},
+		{"", false},
+		{"0abc", false},
+		{"0", false},
+		{"ab-c", false},
+		{"ab c", false},
+		{"zzzzzzzzzzzz", false},
+	}
+
+	for _, c := range cases {
+		rt.Run(c.id, func(t *testing.T) {
+			assert.Equal(t, c.valid, evepraisal.ValidAppraisalID(c.id))
+		})
+	}
+}
+
+func TestPutAppraisals(t *testing.T) {
+	db, cleanup := newTestAppraisalDB(t)
+	defer cleanup()
+
+	assert.NoError(t, db.PutNewAppraisal(&evepraisal.Appraisal{ID: "taken", Kind: "listing"}))
+
+	user := &evepraisal.User{CharacterName: "Some Pilot", CharacterOwnerHash: "hash"}
+	badUser := &evepraisal.User{CharacterName: "Some Pilot", CharacterOwnerHash: strings.Repeat("x", bolt.MaxKeySize)}
+	appraisals := []*evepraisal.Appraisal{
+		{ID: "new1", Kind: "listing", User: user},
+		{ID: "NEW1", Kind: "listing"},
+		{ID: "taken", Kind: "listing"},
+		{ID: "bad!", Kind: "listing"},
+		{ID: "0new2", Kind: "listing"},
+		{ID: "zzzzzzzzzzzz", Kind: "listing"},
+		{ID: "new3", Kind: "listing", User: badUser},
+		{Kind: "listing"},
+	}
+	errs, err := db.PutAppraisals(appraisals)
+	assert.NoError(t, err)
+	assert.Equal(t, []error{
+		nil,
+		evepraisal.AppraisalExists,
+		evepraisal.AppraisalExists,
+		evepraisal.InvalidAppraisalID,
+		evepraisal.InvalidAppraisalID,
+		evepraisal.InvalidAppraisalID,
+		errs[6],
+		nil,
+	}, errs)
+	assert.EqualError(t, errs[6], "character owner hash is too long")
+
+	stored, err := db.GetAppraisal("new1")
+	assert.NoError(t, err)
+	assert.Equal(t, "new1", stored.ID)
+
+	// The skipped records left nothing behind
+	for _, id := range []string{"new2", "new3"} {
+		_, err = db.GetAppraisal(id)
+		assert.Equal(t, evepraisal.AppraisalNotFound, err, id)
+	}
+	db.DB.View(func(tx *bolt.Tx) error {
+		assert.Equal(t, 1, tx.Bucket([]byte("appraisals-by-user")).Stats().KeyN)
+		return nil
+	})
+
+	// New appraisals are numbered after the imported ones
+	assert.True(t, evepraisal.AppraisalIDToUint64(appraisals[7].ID) > evepraisal.AppraisalIDToUint64("new1"))
+	assert.NoError(t, db.PutNewAppraisal(&evepraisal.Appraisal{Kind: "listing"}))
+}
+
+func TestAppraisalKindFilter(t *testing.T) {
+	db, cleanup := newTestAppraisalDB(t)
+	defer cleanup()
+
+	user := &evepraisal.User{CharacterName: "Some Pilot", CharacterOwnerHash: "hash"}
+	for _, kind := range []string{"listing", "view_contents", "wallet"} {
+		assert.NoError(t, db.PutNewAppraisal(&evepraisal.Appraisal{Kind: kind, User: user}))
+	}
+
+	kinds := func(appraisals []evepraisal.Appraisal) []string {
+		result := make([]string, len(appraisals))
+		for i, appraisal := range appraisals {
+			result[i] = appraisal.Kind
+		}
+		return result
+	}
+
+	cases := []struct {
+		kind     string
+		expected []string
+	}{
+		{"", []string{"wallet", "view_contents", "listing"}},
+		{"wallet", []string{"wallet", "view_contents"}},
+		{"view_contents", []string{"view_contents"}},
+		{"listing", []string{"listing"}},
+	}
+	for _, c := range cases {
+		appraisals, err := db.LatestAppraisals(10, c.kind)
+		assert.NoError(t, err)
+		assert.Equal(t, c.expected, kinds(appraisals), c.kind)
+
+		appraisals, err = db.LatestAppraisalsByUser(*user, 10, c.kind, "")
+		assert.NoError(t, err)
+		assert.Equal(t, c.expected, kinds(appraisals), c.kind)
+
+		var exported []string
+		err = db.ForEachAppraisal(time.Unix(0, 0), c.kind, func(appraisal *evepraisal.Appraisal) error {
+			exported = append([]string{appraisal.Kind}, exported...)
+			return nil
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, c.expected, exported, c.kind)
+	}
+}
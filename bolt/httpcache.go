@@ -1,8 +1,13 @@
 package bolt
 
 import (
+	"encoding/binary"
 	"errors"
+	"expvar"
 	"fmt"
+	"log"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/boltdb/bolt"
@@ -12,25 +17,99 @@ var (
 	CacheKeyNotFound = errors.New("Cache key not found, cache miss")
 )
 
+var (
+	httpCacheStats     = expvar.NewMap("httpcache")
+	httpCacheSizeStats = new(expvar.Int)
+)
+
+func init() {
+	httpCacheStats.Set("size", httpCacheSizeStats)
+}
+
+// HTTPCacheOptions controls how long entries stay in the cache and how big it can get.
+// A zero value for any of the options disables that limit.
+type HTTPCacheOptions struct {
+	TTL           time.Duration
+	MaxSize       int64
+	SweepInterval time.Duration
+}
+
+// HTTPCache is a bolt backed cache for github.com/gregjones/httpcache. Every entry has an insertion
+// timestamp (in the httpcache-meta bucket) which is also indexed in the httpcache-by-time bucket so that
+// expired and oldest entries can be removed without scanning the whole cache.
 type HTTPCache struct {
-	db *bolt.DB
+	db   *bolt.DB
+	opts HTTPCacheOptions
+	size int64
+
+	stop chan bool
+	wg   *sync.WaitGroup
 }
 
-func NewHTTPCache(filename string) (*HTTPCache, error) {
+func NewHTTPCache(filename string, opts HTTPCacheOptions) (*HTTPCache, error) {
 	db, err := bolt.Open(filename, 0600, &bolt.Options{Timeout: 1 * time.Second})
 	if err != nil {
 		return nil, err
 	}
 
+	c := &HTTPCache{
+		db:   db,
+		opts: opts,
+		stop: make(chan bool),
+		wg:   &sync.WaitGroup{},
+	}
+
 	err = db.Update(func(tx *bolt.Tx) error {
-		_, err := tx.CreateBucketIfNotExists([]byte("httpcache"))
+		b, err := tx.CreateBucketIfNotExists([]byte("httpcache"))
 		if err != nil {
 			return fmt.Errorf("create httpcache bucket: %s", err)
 		}
-		return nil
+		metaBucket, err := tx.CreateBucketIfNotExists([]byte("httpcache-meta"))
+		if err != nil {
+			return fmt.Errorf("create httpcache-meta bucket: %s", err)
+		}
+		byTimeBucket, err := tx.CreateBucketIfNotExists([]byte("httpcache-by-time"))
+		if err != nil {
+			return fmt.Errorf("create httpcache-by-time bucket: %s", err)
+		}
+
+		// Entries written before timestamps existed are treated as if they were inserted now
+		now := time.Now()
+		backfilled := 0
+		err = b.ForEach(func(key, val []byte) error {
+			meta := metaBucket.Get(key)
+			if meta == nil {
+				backfilled++
+				meta = encodeCacheMeta(now, int64(len(val)))
+				err := metaBucket.Put(key, meta)
+				if err != nil {
+					return err
+				}
+				err = byTimeBucket.Put(cacheTimeKey(meta, key), []byte{})
+				if err != nil {
+					return err
+				}
+			}
+			_, size := decodeCacheMeta(meta)
+			c.size += size
+			return nil
+		})
+		if backfilled > 0 {
+			log.Printf("Added timestamps to %d httpcache entries", backfilled)
+		}
+		return err
 	})
+	if err != nil {
+		return c, err
+	}
+	httpCacheSizeStats.Set(c.size)
 
-	return &HTTPCache{db}, err
+	if opts.SweepInterval > 0 {
+		c.wg.Add(1)
+		go c.startSweeper()
+	}
+
+	return c, nil
 }
 
 func (c *HTTPCache) Get(key string) (resp []byte, ok bool) {
@@ -43,30 +122,203 @@ func (c *HTTPCache) Get(key string) (resp []byte, ok bool) {
 			return CacheKeyNotFound
 		}
 
+		if c.opts.TTL > 0 {
+			meta := tx.Bucket([]byte("httpcache-meta")).Get([]byte(key))
+			if meta != nil {
+				inserted, _ := decodeCacheMeta(meta)
+				if time.Since(inserted) > c.opts.TTL {
+					return CacheKeyNotFound
+				}
+			}
+		}
+
 		// we need to copy the byte array because it might be re-used outside of this view function
 		result = make([]byte, len(tmp))
 		copy(result, tmp)
 		return nil
 	})
 	if err != nil || result == nil {
+		httpCacheStats.Add("misses", 1)
 		return nil, false
 	}
 
+	httpCacheStats.Add("hits", 1)
 	return result, true
 }
 
 func (c *HTTPCache) Set(key string, resp []byte) {
-	c.db.Update(func(tx *bolt.Tx) error {
-		return tx.Bucket([]byte("httpcache")).Put([]byte(key), resp)
+	var sizeChange int64
+	err := c.db.Update(func(tx *bolt.Tx) error {
+		freed, err := c.delete(tx, []byte(key))
+		if err != nil {
+			return err
+		}
+
+		meta := encodeCacheMeta(time.Now(), int64(len(resp)))
+		err = tx.Bucket([]byte("httpcache")).Put([]byte(key), resp)
+		if err != nil {
+			return err
+		}
+		err = tx.Bucket([]byte("httpcache-meta")).Put([]byte(key), meta)
+		if err != nil {
+			return err
+		}
+		err = tx.Bucket([]byte("httpcache-by-time")).Put(cacheTimeKey(meta, []byte(key)), []byte{})
+		if err != nil {
+			return err
+		}
+		sizeChange = int64(len(resp)) - freed
+		return nil
 	})
+	if err != nil {
+		log.Printf("WARNING: Unable to write to httpcache: %s", err)
+		return
+	}
+	atomic.AddInt64(&c.size, sizeChange)
+
+	if c.opts.MaxSize > 0 && atomic.LoadInt64(&c.size) > c.opts.MaxSize {
+		c.evict()
+	}
+	httpCacheSizeStats.Set(atomic.LoadInt64(&c.size))
 }
 
 func (c *HTTPCache) Delete(key string) {
-	c.db.Update(func(tx *bolt.Tx) error {
-		return tx.Bucket([]byte("httpcache")).Delete([]byte(key))
+	var freed int64
+	err := c.db.Update(func(tx *bolt.Tx) error {
+		var err error
+		freed, err = c.delete(tx, []byte(key))
+		return err
 	})
+	if err == nil {
+		atomic.AddInt64(&c.size, -freed)
+	}
+	httpCacheSizeStats.Set(atomic.LoadInt64(&c.size))
 }
 
 func (c *HTTPCache) Close() error {
+	close(c.stop)
+	c.wg.Wait()
 	return c.db.Close()
 }
+
+// delete removes an entry in the transaction and returns its size. The size of the cache is only changed by the
+// callers once the transaction is committed, so a rollback can't make it drift.
+func (c *HTTPCache) delete(tx *bolt.Tx, key []byte) (int64, error) {
+	var size int64
+	metaBucket := tx.Bucket([]byte("httpcache-meta"))
+	meta := metaBucket.Get(key)
+	if meta != nil {
+		_, size = decodeCacheMeta(meta)
+		err := tx.Bucket([]byte("httpcache-by-time")).Delete(cacheTimeKey(meta, key))
+		if err != nil {
+			return 0, err
+		}
+		err = metaBucket.Delete(key)
+		if err != nil {
+			return 0, err
+		}
+	}
+	return size, tx.Bucket([]byte("httpcache")).Delete(key)
+}
+
+// evict removes the oldest entries until the cache is below its maximum size
+func (c *HTTPCache) evict() {
+	var evicted int
+	var freed int64
+	err := c.db.Update(func(tx *bolt.Tx) error {
+		evicted, freed = 0, 0
+		size := atomic.LoadInt64(&c.size)
+		cursor := tx.Bucket([]byte("httpcache-by-time")).Cursor()
+		for timeKey, _ := cursor.First(); timeKey != nil && size-freed > c.opts.MaxSize; timeKey, _ = cursor.First() {
+			key := make([]byte, len(timeKey)-8)
+			copy(key, timeKey[8:])
+			entrySize, err := c.delete(tx, key)
+			if err != nil {
+				return err
+			}
+			freed += entrySize
+			evicted++
+		}
+		return nil
+	})
+	if err != nil {
+		log.Printf("WARNING: Unable to evict httpcache entries: %s", err)
+		return
+	}
+	atomic.AddInt64(&c.size, -freed)
+	httpCacheStats.Add("evictions", int64(evicted))
+}
+
+// expire removes all entries that are older than the TTL
+func (c *HTTPCache) expire() {
+	var expired int
+	var freed int64
+	cutoff := time.Now().Add(-c.opts.TTL)
+	err := c.db.Update(func(tx *bolt.Tx) error {
+		expired, freed = 0, 0
+		cursor := tx.Bucket([]byte("httpcache-by-time")).Cursor()
+		for timeKey, _ := cursor.First(); timeKey != nil; timeKey, _ = cursor.First() {
+			inserted, _ := decodeCacheMeta(timeKey[:8])
+			if inserted.After(cutoff) {
+				break
+			}
+
+			key := make([]byte, len(timeKey)-8)
+			copy(key, timeKey[8:])
+			entrySize, err := c.delete(tx, key)
+			if err != nil {
+				return err
+			}
+			freed += entrySize
+			expired++
+		}
+		return nil
+	})
+	if err != nil {
+		log.Printf("WARNING: Unable to expire httpcache entries: %s", err)
+		return
+	}
+	atomic.AddInt64(&c.size, -freed)
+	httpCacheStats.Add("expirations", int64(expired))
+}
+
+func (c *HTTPCache) startSweeper() {
+	defer c.wg.Done()
+	for {
+		select {
+		case <-c.stop:
+			return
+		case <-time.After(c.opts.SweepInterval):
+		}
+
+		if c.opts.TTL > 0 {
+			c.expire()
+		}
+		if c.opts.MaxSize > 0 && atomic.LoadInt64(&c.size) > c.opts.MaxSize {
+			c.evict()
+		}
+		httpCacheSizeStats.Set(atomic.LoadInt64(&c.size))
+	}
+}
+
+// encodeCacheMeta packs the insertion time and size of an entry; the first 8 bytes sort by time
+func encodeCacheMeta(inserted time.Time, size int64) []byte {
+	meta := make([]byte, 16)
+	binary.BigEndian.PutUint64(meta[:8], uint64(inserted.UnixNano()))
+	binary.BigEndian.PutUint64(meta[8:], uint64(size))
+	return meta
+}
+
+func decodeCacheMeta(meta []byte) (inserted time.Time, size int64) {
+	inserted = time.Unix(0, int64(binary.BigEndian.Uint64(meta[:8])))
+	if len(meta) >= 16 {
+		size = int64(binary.BigEndian.Uint64(meta[8:16]))
+	}
+	return inserted, size
+}
+
+func cacheTimeKey(meta []byte, key []byte) []byte {
+	timeKey := make([]byte, 0, 8+len(key))
+	timeKey = append(timeKey, meta[:8]...)
+	return append(timeKey, key...)
+}
@@ -0,0 +1,220 @@
+package bolt
+
+import (
+	"expvar"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/boltdb/bolt"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestHTTPCache(t *testing.T, opts HTTPCacheOptions) (*HTTPCache, func()) {
+	dir, err := ioutil.TempDir("", "httpcache")
+	assert.NoError(t, err)
+	c, err := NewHTTPCache(filepath.Join(dir, "httpcache"), opts)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	return c, func() {
+		c.Close()
+		os.RemoveAll(dir)
+	}
+}
+
+// putCacheEntry writes an entry as if it was set at the given time
+func putCacheEntry(t *testing.T, c *HTTPCache, key string, val []byte, inserted time.Time) {
+	err := c.db.Update(func(tx *bolt.Tx) error {
+		freed, err := c.delete(tx, []byte(key))
+		if err != nil {
+			return err
+		}
+		c.size -= freed
+
+		meta := encodeCacheMeta(inserted, int64(len(val)))
+		tx.Bucket([]byte("httpcache")).Put([]byte(key), val)
+		tx.Bucket([]byte("httpcache-meta")).Put([]byte(key), meta)
+		c.size += int64(len(val))
+		return tx.Bucket([]byte("httpcache-by-time")).Put(cacheTimeKey(meta, []byte(key)), []byte{})
+	})
+	assert.NoError(t, err)
+}
+
+func cacheKeys(t *testing.T, c *HTTPCache) []string {
+	keys := make([]string, 0)
+	c.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket([]byte("httpcache")).ForEach(func(key, val []byte) error {
+			keys = append(keys, string(key))
+			return nil
+		})
+	})
+	return keys
+}
+
+// cacheIndexCounts returns how many entries there are in the meta and by-time buckets
+func cacheIndexCounts(c *HTTPCache) (meta int, byTime int) {
+	c.db.View(func(tx *bolt.Tx) error {
+		meta = tx.Bucket([]byte("httpcache-meta")).Stats().KeyN
+		byTime = tx.Bucket([]byte("httpcache-by-time")).Stats().KeyN
+		return nil
+	})
+	return meta, byTime
+}
+
+func httpCacheStat(name string) int64 {
+	v, ok := httpCacheStats.Get(name).(*expvar.Int)
+	if !ok {
+		return 0
+	}
+	return v.Value()
+}
+
+func TestHTTPCacheGetTTL(rt *testing.T) {
+	cases := []struct {
+		name  string
+		ttl   time.Duration
+		age   time.Duration
+		found bool
+	}{
+		{"no ttl", 0, 100 * time.Hour, true},
+		{"fresh", time.Hour, time.Minute, true},
+		{"expired", time.Hour, 2 * time.Hour, false},
+	}
+
+	for _, c := range cases {
+		rt.Run(c.name, func(t *testing.T) {
+			cache, cleanup := newTestHTTPCache(t, HTTPCacheOptions{TTL: c.ttl})
+			defer cleanup()
+
+			putCacheEntry(t, cache, "key", []byte("value"), time.Now().Add(-c.age))
+			hits, misses := httpCacheStat("hits"), httpCacheStat("misses")
+
+			resp, ok := cache.Get("key")
+			assert.Equal(t, c.found, ok)
+			if c.found {
+				assert.Equal(t, []byte("value"), resp)
+				assert.Equal(t, hits+1, httpCacheStat("hits"))
+			} else {
+				assert.Nil(t, resp)
+				assert.Equal(t, misses+1, httpCacheStat("misses"))
+			}
+		})
+	}
+}
+
+func TestHTTPCacheSize(t *testing.T) {
+	cache, cleanup := newTestHTTPCache(t, HTTPCacheOptions{})
+	defer cleanup()
+
+	steps := []struct {
+		name string
+		do   func()
+		size int64
+	}{
+		{"set", func() { cache.Set("a", make([]byte, 10)) }, 10},
+		{"overwrite", func() { cache.Set("a", make([]byte, 8)) }, 8},
+		{"set another", func() { cache.Set("b", make([]byte, 5)) }, 13},
+		{"delete", func() { cache.Delete("a") }, 5},
+		{"delete missing", func() { cache.Delete("missing") }, 5},
+		{"delete last", func() { cache.Delete("b") }, 0},
+	}
+
+	for _, step := range steps {
+		step.do()
+		assert.Equal(t, step.size, cache.size, step.name)
+		assert.Equal(t, step.size, httpCacheSizeStats.Value(), step.name)
+	}
+
+	meta, byTime := cacheIndexCounts(cache)
+	assert.Equal(t, 0, meta)
+	assert.Equal(t, 0, byTime)
+}
+
+func TestHTTPCacheEvict(t *testing.T) {
+	cache, cleanup := newTestHTTPCache(t, HTTPCacheOptions{MaxSize: 25})
+	defer cleanup()
+
+	start := time.Now().Add(-time.Hour)
+	putCacheEntry(t, cache, "c", make([]byte, 10), start)
+	putCacheEntry(t, cache, "a", make([]byte, 10), start.Add(time.Minute))
+	evictions := httpCacheStat("evictions")
+
+	// The oldest entry goes first, regardless of key order
+	cache.Set("b", make([]byte, 10))
+	assert.Equal(t, []string{"a", "b"}, cacheKeys(t, cache))
+	assert.Equal(t, int64(20), cache.size)
+	assert.Equal(t, evictions+1, httpCacheStat("evictions"))
+
+	// Evict as many as needed to get below the limit
+	cache.Set("d", make([]byte, 24))
+	assert.Equal(t, []string{"d"}, cacheKeys(t, cache))
+	assert.Equal(t, int64(24), cache.size)
+	assert.Equal(t, evictions+3, httpCacheStat("evictions"))
+
+	meta, byTime := cacheIndexCounts(cache)
+	assert.Equal(t, 1, meta)
+	assert.Equal(t, 1, byTime)
+}
+
+func TestHTTPCacheExpire(t *testing.T) {
+	cache, cleanup := newTestHTTPCache(t, HTTPCacheOptions{TTL: time.Hour})
+	defer cleanup()
+
+	now := time.Now()
+	putCacheEntry(t, cache, "old", make([]byte, 3), now.Add(-3*time.Hour))
+	putCacheEntry(t, cache, "older", make([]byte, 4), now.Add(-2*time.Hour))
+	putCacheEntry(t, cache, "new", make([]byte, 5), now.Add(-time.Minute))
+	expirations := httpCacheStat("expirations")
+
+	cache.expire()
+	assert.Equal(t, []string{"new"}, cacheKeys(t, cache))
+	assert.Equal(t, int64(5), cache.size)
+	assert.Equal(t, expirations+2, httpCacheStat("expirations"))
+
+	meta, byTime := cacheIndexCounts(cache)
+	assert.Equal(t, 1, meta)
+	assert.Equal(t, 1, byTime)
+}
+
+func TestHTTPCacheBackfill(t *testing.T) {
+	dir, err := ioutil.TempDir("", "httpcache")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	filename := filepath.Join(dir, "httpcache")
+
+	// A cache from before entries had timestamps
+	db, err := bolt.Open(filename, 0600, nil)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.NoError(t, db.Update(func(tx *bolt.Tx) error {
+		b, err := tx.CreateBucket([]byte("httpcache"))
+		if err != nil {
+			return err
+		}
+		b.Put([]byte("a"), make([]byte, 3))
+		return b.Put([]byte("b"), make([]byte, 4))
+	}))
+	assert.NoError(t, db.Close())
+
+	cache, err := NewHTTPCache(filename, HTTPCacheOptions{TTL: time.Hour})
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer cache.Close()
+
+	assert.Equal(t, int64(7), cache.size)
+	assert.Equal(t, int64(7), httpCacheSizeStats.Value())
+	meta, byTime := cacheIndexCounts(cache)
+	assert.Equal(t, 2, meta)
+	assert.Equal(t, 2, byTime)
+
+	// Backfilled entries count as new, so they don't expire right away
+	cache.expire()
+	assert.Equal(t, []string{"a", "b"}, cacheKeys(t, cache))
+	_, ok := cache.Get("a")
+	assert.True(t, ok)
+}
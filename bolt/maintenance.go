@@ -0,0 +1,249 @@
+package bolt
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/boltdb/bolt"
+)
+
+// appraisalIndexBuckets are keyed (or valued, for appraisals-by-user) by appraisal database ID
+var appraisalIndexBuckets = []string{
+	"appraisals-by-user",
+	"appraisals-last-used",
+	"appraisals-notified-time",
+	"appraisals-notified-status",
+}
+
+// CheckReport is the result of checking a database file
+type CheckReport struct {
+	Errors  []string
+	Orphans map[string]int
+}
+
+// Compact copies all live buckets of a database into a fresh file and swaps it in place of the original.
+// It returns the file sizes before and after. The database must not be in use.
+func Compact(filename string) (before int64, after int64, err error) {
+	// bolt.Open creates missing files, so make sure there is something to compact first
+	stat, err := os.Stat(filename)
+	if err != nil {
+		return 0, 0, err
+	}
+	before = stat.Size()
+
+	src, err := bolt.Open(filename, 0600, &bolt.Options{Timeout: 1 * time.Second})
+	if err != nil {
+		return 0, 0, fmt.Errorf("unable to open %s, is it still in use? (%s)", filename, err)
+	}
+	defer src.Close()
+
+	tmpFilename := filename + ".compact"
+	os.Remove(tmpFilename)
+	dst, err := bolt.Open(tmpFilename, 0600, &bolt.Options{Timeout: 1 * time.Second})
+	if err != nil {
+		return before, 0, err
+	}
+
+	err = src.View(func(srcTx *bolt.Tx) error {
+		return compactInto(srcTx, dst, compactTxMaxSize)
+	})
+	if err != nil {
+		dst.Close()
+		os.Remove(tmpFilename)
+		return before, 0, err
+	}
+
+	err = dst.Close()
+	if err != nil {
+		os.Remove(tmpFilename)
+		return before, 0, err
+	}
+
+	err = os.Rename(tmpFilename, filename)
+	if err != nil {
+		return before, 0, err
+	}
+
+	stat, err = os.Stat(filename)
+	if err != nil {
+		return before, 0, err
+	}
+	return before, stat.Size(), nil
+}
+
+// compactTxMaxSize is how many bytes of keys and values are copied in one transaction. Committing in batches
+// keeps compacting from holding the whole database in memory.
+const compactTxMaxSize = 64 * 1024 * 1024
+
+// compactor copies buckets into dst, committing whenever a transaction has reached maxSize
+type compactor struct {
+	dst     *bolt.DB
+	tx      *bolt.Tx
+	size    int64
+	maxSize int64
+}
+
+func compactInto(srcTx *bolt.Tx, dst *bolt.DB, maxSize int64) error {
+	tx, err := dst.Begin(true)
+	if err != nil {
+		return err
+	}
+
+	c := &compactor{dst: dst, tx: tx, maxSize: maxSize}
+	err = srcTx.ForEach(func(name []byte, b *bolt.Bucket) error {
+		path := [][]byte{name}
+		err := c.createBucket(path, b.Sequence())
+		if err != nil {
+			return err
+		}
+		return c.copyBucket(b, path)
+	})
+	if err != nil {
+		c.tx.Rollback()
+		return err
+	}
+	return c.tx.Commit()
+}
+
+// reserve makes room for size bytes in the current transaction, committing it and starting a new one if it is full
+func (c *compactor) reserve(size int64) error {
+	if c.size > 0 && c.size+size > c.maxSize {
+		err := c.tx.Commit()
+		if err != nil {
+			return err
+		}
+		c.tx, err = c.dst.Begin(true)
+		if err != nil {
+			return err
+		}
+		c.size = 0
+	}
+	c.size += size
+	return nil
+}
+
+// bucket looks up the bucket at path in the current transaction, since buckets don't outlive a transaction
+func (c *compactor) bucket(path [][]byte) *bolt.Bucket {
+	b := c.tx.Bucket(path[0])
+	for _, name := range path[1:] {
+		b = b.Bucket(name)
+	}
+	// Mostly sequential keys, so fill pages up all the way
+	b.FillPercent = 1.0
+	return b
+}
+
+func (c *compactor) createBucket(path [][]byte, sequence uint64) error {
+	err := c.reserve(int64(len(path[len(path)-1])))
+	if err != nil {
+		return err
+	}
+
+	var b *bolt.Bucket
+	if len(path) == 1 {
+		b, err = c.tx.CreateBucket(path[0])
+	} else {
+		b, err = c.bucket(path[:len(path)-1]).CreateBucket(path[len(path)-1])
+	}
+	if err != nil {
+		return err
+	}
+	return b.SetSequence(sequence)
+}
+
+func (c *compactor) copyBucket(src *bolt.Bucket, path [][]byte) error {
+	return src.ForEach(func(key, val []byte) error {
+		// Values of nested buckets are nil, but so can be empty values
+		if val == nil && src.Bucket(key) != nil {
+			nestedPath := append(append([][]byte{}, path...), key)
+			err := c.createBucket(nestedPath, src.Bucket(key).Sequence())
+			if err != nil {
+				return err
+			}
+			return c.copyBucket(src.Bucket(key), nestedPath)
+		}
+
+		err := c.reserve(int64(len(key) + len(val)))
+		if err != nil {
+			return err
+		}
+		return c.bucket(path).Put(key, val)
+	})
+}
+
+// Check verifies the page structure of a database. For the appraisal database it also looks for index entries
+// that point at appraisals which don't exist anymore and deletes them if repair is set.
+func Check(filename string, repair bool) (CheckReport, error) {
+	report := CheckReport{Errors: make([]string, 0), Orphans: make(map[string]int)}
+
+	_, err := os.Stat(filename)
+	if err != nil {
+		return report, err
+	}
+
+	db, err := bolt.Open(filename, 0600, &bolt.Options{Timeout: 1 * time.Second, ReadOnly: !repair})
+	if err != nil {
+		return report, fmt.Errorf("unable to open %s, is it still in use? (%s)", filename, err)
+	}
+	defer db.Close()
+
+	orphans := make(map[string][][]byte)
+	err = db.View(func(tx *bolt.Tx) error {
+		for err := range tx.Check() {
+			report.Errors = append(report.Errors, err.Error())
+		}
+
+		byIDBucket := tx.Bucket([]byte("appraisals"))
+		if byIDBucket == nil {
+			return nil
+		}
+
+		for _, bucketName := range appraisalIndexBuckets {
+			b := tx.Bucket([]byte(bucketName))
+			if b == nil {
+				continue
+			}
+
+			err := b.ForEach(func(key, val []byte) error {
+				dbID := key
+				if bucketName == "appraisals-by-user" {
+					dbID = val
+				}
+
+				if byIDBucket.Get(dbID) == nil {
+					// Keys are only valid for the life of the transaction
+					orphan := make([]byte, len(key))
+					copy(orphan, key)
+					orphans[bucketName] = append(orphans[bucketName], orphan)
+				}
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+			report.Orphans[bucketName] = len(orphans[bucketName])
+		}
+		return nil
+	})
+	if err != nil {
+		return report, err
+	}
+
+	if !repair || len(orphans) == 0 {
+		return report, nil
+	}
+
+	return report, db.Update(func(tx *bolt.Tx) error {
+		for bucketName, keys := range orphans {
+			b := tx.Bucket([]byte(bucketName))
+			for _, key := range keys {
+				err := b.Delete(key)
+				if err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+}
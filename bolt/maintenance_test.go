@@ -0,0 +1,204 @@
+package bolt
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/boltdb/bolt"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestBoltFile(t *testing.T, fill func(tx *bolt.Tx) error) (string, func()) {
+	dir, err := ioutil.TempDir("", "maintenance")
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	filename := filepath.Join(dir, "db")
+
+	db, err := bolt.Open(filename, 0600, nil)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	assert.NoError(t, db.Update(fill))
+	assert.NoError(t, db.Close())
+	return filename, func() { os.RemoveAll(dir) }
+}
+
+func TestCompact(t *testing.T) {
+	filename, cleanup := newTestBoltFile(t, func(tx *bolt.Tx) error {
+		a, err := tx.CreateBucket([]byte("a"))
+		if err != nil {
+			return err
+		}
+		a.SetSequence(42)
+		nested, err := a.CreateBucket([]byte("nested"))
+		if err != nil {
+			return err
+		}
+		nested.SetSequence(7)
+		for i := 0; i < 10000; i++ {
+			a.Put([]byte(fmt.Sprintf("k%05d", i)), make([]byte, 100))
+			nested.Put([]byte(fmt.Sprintf("n%05d", i)), []byte("x"))
+		}
+		a.Put([]byte("empty"), []byte{})
+		return nil
+	})
+	defer cleanup()
+
+	// Free most of the pages so there is something to reclaim
+	db, err := bolt.Open(filename, 0600, nil)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.NoError(t, db.Update(func(tx *bolt.Tx) error {
+		a := tx.Bucket([]byte("a"))
+		for i := 100; i < 10000; i++ {
+			a.Delete([]byte(fmt.Sprintf("k%05d", i)))
+		}
+		return nil
+	}))
+	assert.NoError(t, db.Close())
+
+	before, after, err := Compact(filename)
+	assert.NoError(t, err)
+	assert.True(t, after < before, "%d should be smaller than %d", after, before)
+
+	db, err = bolt.Open(filename, 0600, nil)
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer db.Close()
+	db.View(func(tx *bolt.Tx) error {
+		a := tx.Bucket([]byte("a"))
+		if !assert.NotNil(t, a) {
+			return nil
+		}
+		assert.Equal(t, uint64(42), a.Sequence())
+		assert.Equal(t, make([]byte, 100), a.Get([]byte("k00099")))
+		assert.Nil(t, a.Get([]byte("k00100")))
+
+		empty := a.Get([]byte("empty"))
+		assert.NotNil(t, empty, "empty values should be copied as values, not buckets")
+		assert.Len(t, empty, 0)
+
+		nested := a.Bucket([]byte("nested"))
+		if !assert.NotNil(t, nested) {
+			return nil
+		}
+		assert.Equal(t, uint64(7), nested.Sequence())
+		assert.Equal(t, 10000, nested.Stats().KeyN)
+		assert.Equal(t, []byte("x"), nested.Get([]byte("n09999")))
+		return nil
+	})
+
+	_, err = os.Stat(filename + ".compact")
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestCompactInBatches(t *testing.T) {
+	filename, cleanup := newTestBoltFile(t, func(tx *bolt.Tx) error {
+		a, err := tx.CreateBucket([]byte("a"))
+		if err != nil {
+			return err
+		}
+		for i := 0; i < 1000; i++ {
+			a.Put([]byte(fmt.Sprintf("k%04d", i)), []byte("value"))
+		}
+		return nil
+	})
+	defer cleanup()
+
+	src, err := bolt.Open(filename, 0600, nil)
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer src.Close()
+	dst, err := bolt.Open(filename+".dst", 0600, nil)
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer dst.Close()
+
+	assert.NoError(t, src.View(func(tx *bolt.Tx) error { return compactInto(tx, dst, 100) }))
+	dst.View(func(tx *bolt.Tx) error {
+		assert.Equal(t, 1000, tx.Bucket([]byte("a")).Stats().KeyN)
+		return nil
+	})
+}
+
+func TestCompactMissingFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "maintenance")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	filename := filepath.Join(dir, "missing")
+	_, _, err = Compact(filename)
+	assert.Error(t, err)
+	_, err = Check(filename, true)
+	assert.Error(t, err)
+
+	_, err = os.Stat(filename)
+	assert.True(t, os.IsNotExist(err), "a missing database should not be created")
+}
+
+func TestCheckOrphans(t *testing.T) {
+	filename, cleanup := newTestBoltFile(t, func(tx *bolt.Tx) error {
+		appraisals, err := tx.CreateBucket([]byte("appraisals"))
+		if err != nil {
+			return err
+		}
+		appraisals.Put([]byte("live"), []byte("{}"))
+
+		for _, name := range appraisalIndexBuckets {
+			b, err := tx.CreateBucket([]byte(name))
+			if err != nil {
+				return err
+			}
+			if name == "appraisals-by-user" {
+				b.Put([]byte("user:live"), []byte("live"))
+				b.Put([]byte("user:gone"), []byte("gone"))
+			} else {
+				b.Put([]byte("live"), []byte("1"))
+				b.Put([]byte("gone"), []byte("1"))
+			}
+		}
+		return nil
+	})
+	defer cleanup()
+
+	report, err := Check(filename, false)
+	assert.NoError(t, err)
+	assert.Empty(t, report.Errors)
+	for _, name := range appraisalIndexBuckets {
+		assert.Equal(t, 1, report.Orphans[name], name)
+	}
+
+	// Without repair nothing is deleted
+	report, err = Check(filename, false)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, report.Orphans["appraisals-by-user"])
+
+	report, err = Check(filename, true)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, report.Orphans["appraisals-last-used"])
+
+	report, err = Check(filename, false)
+	assert.NoError(t, err)
+	for _, name := range appraisalIndexBuckets {
+		assert.Equal(t, 0, report.Orphans[name], name)
+	}
+
+	db, err := bolt.Open(filename, 0600, nil)
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer db.Close()
+	db.View(func(tx *bolt.Tx) error {
+		assert.Equal(t, []byte("live"), tx.Bucket([]byte("appraisals-by-user")).Get([]byte("user:live")))
+		assert.Equal(t, []byte("1"), tx.Bucket([]byte("appraisals-last-used")).Get([]byte("live")))
+		return nil
+	})
+}
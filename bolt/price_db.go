@@ -15,8 +15,20 @@ type PriceDB struct {
 	db *bolt.DB
 }
 
-func NewPriceDB(filename string) (evepraisal.PriceDB, error) {
-	db, err := bolt.Open(filename, 0600, &bolt.Options{Timeout: 1 * time.Second})
+// NewPriceDB opens the price database. A read-only price database can be opened next to other readers, but
+// prices can't be updated through it.
+func NewPriceDB(filename string, writable bool) (evepraisal.PriceDB, error) {
+	opts := &bolt.Options{Timeout: 1 * time.Second}
+	if !writable {
+		opts.ReadOnly = true
+		db, err := bolt.Open(filename, 0600, opts)
+		if err != nil {
+			return nil, err
+		}
+		return &PriceDB{db: db}, nil
+	}
+
+	db, err := bolt.Open(filename, 0600, opts)
 	if err != nil {
 		return nil, err
 	}
@@ -38,6 +50,9 @@ func (db *PriceDB) GetPrice(market string, typeID int64) (evepraisal.Prices, boo
 	err := db.db.View(func(tx *bolt.Tx) error {
 		var err error
 		b := tx.Bucket([]byte("prices"))
+		if b == nil {
+			return errors.New("Price not found")
+		}
 		buf := b.Get([]byte(fmt.Sprintf("%s|%d", market, typeID)))
 		if buf == nil {
 			return errors.New("Price not found")
@@ -0,0 +1,51 @@
+package bolt
+
+import (
+	"io"
+
+	"github.com/boltdb/bolt"
+)
+
+// snapshot writes a consistent copy of the database to w from inside of a read-only transaction, so
+// it is safe to call while the database is in use
+func snapshot(db *bolt.DB, w io.Writer) (int64, error) {
+	return sizedSnapshot(db, func(int64) io.Writer { return w })
+}
+
+// sizedSnapshot is like snapshot but passes the size of the snapshot to start before writing to the
+// writer it returns
+func sizedSnapshot(db *bolt.DB, start func(size int64) io.Writer) (int64, error) {
+	var n int64
+	err := db.View(func(tx *bolt.Tx) error {
+		var err error
+		n, err = tx.WriteTo(start(tx.Size()))
+		return err
+	})
+	return n, err
+}
+
+// Snapshot writes a consistent copy of the appraisal database to w
+func (db *AppraisalDB) Snapshot(w io.Writer) (int64, error) {
+	return snapshot(db.DB, w)
+}
+
+// SizedSnapshot writes a consistent copy of the appraisal database to the writer returned by start
+func (db *AppraisalDB) SizedSnapshot(start func(size int64) io.Writer) (int64, error) {
+	return sizedSnapshot(db.DB, start)
+}
+
+// Snapshot writes a consistent copy of the price database to w
+func (db *PriceDB) Snapshot(w io.Writer) (int64, error) {
+	return snapshot(db.db, w)
+}
+
+// Snapshot writes a consistent copy of the http cache to w
+func (c *HTTPCache) Snapshot(w io.Writer) (int64, error) {
+	return snapshot(c.db, w)
+}
+
+// Snapshot writes a consistent copy of the type database to w. The search index is not included
+// since it can be rebuilt from the static dump.
+func (db *TypeDB) Snapshot(w io.Writer) (int64, error) {
+	return snapshot(db.db, w)
+}
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"path/filepath"
 	"strconv"
 	"strings"
 	"time"
@@ -194,6 +195,18 @@ func (db *TypeDB) PutType(eveType typedb.EveType) error {
 			return err
 		}
 
+		// Localized names never replace another type's entry, so English names always win
+		for _, name := range eveType.LocalizedNames {
+			key := []byte(strings.ToLower(name))
+			if existing := byName.Get(key); existing != nil && encodedTypeID(existing) != eveType.ID {
+				continue
+			}
+			err := byName.Put(key, typeBytes)
+			if err != nil {
+				return err
+			}
+		}
+
 		byID := tx.Bucket([]byte("types_by_id"))
 		err = byID.Put(encodedEveTypeID, typeBytes)
 		if err != nil {
@@ -206,7 +219,28 @@ func (db *TypeDB) PutType(eveType typedb.EveType) error {
 		return err
 	}
 
-	return db.index.Index(strconv.FormatInt(eveType.ID, 10), eveType.Name)
+	doc := map[string]string{"name": eveType.Name}
+	for lang, name := range eveType.LocalizedNames {
+		doc["name_"+lang] = name
+	}
+	return db.index.Index(strconv.FormatInt(eveType.ID, 10), doc)
+}
+
+// encodedTypeID returns the ID of an encoded type, or 0 if it can't be decoded
+func encodedTypeID(buf []byte) int64 {
+	buf, err := snappy.Decode(nil, buf)
+	if err != nil {
+		return 0
+	}
+
+	var t struct {
+		ID int64 `json:"id"`
+	}
+	err = json.Unmarshal(buf, &t)
+	if err != nil {
+		return 0
+	}
+	return t.ID
 }
 
 func (db *TypeDB) Search(s string) []typedb.EveType {
@@ -246,6 +280,11 @@ func (db *TypeDB) Search(s string) []typedb.EveType {
 	return results
 }
 
+// Version returns the static dump version this database was loaded from, based on its filename
+func (db *TypeDB) Version() string {
+	return strings.TrimPrefix(filepath.Base(db.filename), "types-")
+}
+
 func (db *TypeDB) Delete() error {
 	err := os.RemoveAll(db.filename)
 	if err != nil {
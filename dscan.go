@@ -0,0 +1,234 @@
+package evepraisal
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/evepraisal/go-evepraisal/parsers"
+	"github.com/spf13/viper"
+)
+
+// D-Scan classes that ships and structures are sorted into
+const (
+	DScanClassLogistics = "logistics"
+	DScanClassTackle    = "tackle"
+	DScanClassCapital   = "capital"
+	DScanClassStructure = "structure"
+	DScanClassOther     = "other"
+)
+
+const ShipCategoryID int64 = 6
+
+// Categories of things on d-scan that are structures
+var structureCategoryIDs = map[int64]bool{
+	23: true, // Starbase
+	40: true, // Sovereignty Structures
+	65: true, // Structure
+}
+
+type dscanGroup struct {
+	name  string
+	class string
+}
+
+// dscanGroups names the ship and structure groups that FCs care about and puts them into a class. Groups that
+// aren't listed are in the "other" class.
+var dscanGroups = map[int64]dscanGroup{
+	25:   {"Frigate", DScanClassOther},
+	26:   {"Cruiser", DScanClassOther},
+	27:   {"Battleship", DScanClassOther},
+	28:   {"Hauler", DScanClassOther},
+	29:   {"Capsule", DScanClassOther},
+	30:   {"Titan", DScanClassCapital},
+	31:   {"Shuttle", DScanClassOther},
+	237:  {"Corvette", DScanClassOther},
+	324:  {"Assault Frigate", DScanClassOther},
+	358:  {"Heavy Assault Cruiser", DScanClassOther},
+	365:  {"Control Tower", DScanClassStructure},
+	380:  {"Deep Space Transport", DScanClassOther},
+	419:  {"Combat Battlecruiser", DScanClassOther},
+	420:  {"Destroyer", DScanClassOther},
+	463:  {"Mining Barge", DScanClassOther},
+	485:  {"Dreadnought", DScanClassCapital},
+	513:  {"Freighter", DScanClassCapital},
+	540:  {"Command Ship", DScanClassOther},
+	541:  {"Interdictor", DScanClassTackle},
+	543:  {"Exhumer", DScanClassOther},
+	547:  {"Carrier", DScanClassCapital},
+	659:  {"Supercarrier", DScanClassCapital},
+	830:  {"Covert Ops", DScanClassOther},
+	831:  {"Interceptor", DScanClassTackle},
+	832:  {"Logistics", DScanClassLogistics},
+	833:  {"Force Recon Ship", DScanClassOther},
+	834:  {"Stealth Bomber", DScanClassOther},
+	883:  {"Capital Industrial Ship", DScanClassCapital},
+	893:  {"Electronic Attack Ship", DScanClassTackle},
+	894:  {"Heavy Interdiction Cruiser", DScanClassTackle},
+	898:  {"Black Ops", DScanClassOther},
+	900:  {"Marauder", DScanClassOther},
+	902:  {"Jump Freighter", DScanClassCapital},
+	906:  {"Combat Recon Ship", DScanClassOther},
+	941:  {"Industrial Command Ship", DScanClassOther},
+	963:  {"Strategic Cruiser", DScanClassOther},
+	1201: {"Attack Battlecruiser", DScanClassOther},
+	1202: {"Blockade Runner", DScanClassOther},
+	1283: {"Expedition Frigate", DScanClassOther},
+	1305: {"Tactical Destroyer", DScanClassOther},
+	1404: {"Engineering Complex", DScanClassStructure},
+	1406: {"Refinery", DScanClassStructure},
+	1527: {"Logistics Frigate", DScanClassLogistics},
+	1534: {"Command Destroyer", DScanClassOther},
+	1538: {"Force Auxiliary", DScanClassCapital},
+	1657: {"Citadel", DScanClassStructure},
+	1972: {"Flag Cruiser", DScanClassOther},
+	4594: {"Lancer Dreadnought", DScanClassCapital},
+}
+
+// DScanAnalysis is the fleet composition of a d-scan. Only ships and structures are counted, everything else on
+// the scan (celestials, drones, wrecks, ...) is only added to Ignored. Things closer than the dscan-grid-distance
+// setting are on grid, things further away or without a distance are off grid.
+type DScanAnalysis struct {
+	OnGrid  DScanCount   `json:"on_grid"`
+	OffGrid DScanCount   `json:"off_grid"`
+	Classes []DScanCount `json:"classes"`
+	Groups  []DScanCount `json:"groups"`
+	Hulls   []DScanCount `json:"hulls"`
+	Ignored int64        `json:"ignored"`
+}
+
+// DScanCount is how many of a hull, group or class are on and off grid, and what they are worth. Value is the
+// estimated ISK of the hulls, on grid and off grid together.
+type DScanCount struct {
+	Name    string  `json:"name,omitempty"`
+	TypeID  int64   `json:"typeID,omitempty"`
+	GroupID int64   `json:"group_id,omitempty"`
+	Class   string  `json:"class,omitempty"`
+	OnGrid  int64   `json:"on_grid"`
+	OffGrid int64   `json:"off_grid"`
+	Value   float64 `json:"value"`
+}
+
+// Total is the number of hulls, on grid and off grid
+func (c DScanCount) Total() int64 {
+	return c.OnGrid + c.OffGrid
+}
+
+func (c *DScanCount) add(onGrid bool, value float64) {
+	if onGrid {
+		c.OnGrid++
+	} else {
+		c.OffGrid++
+	}
+	c.Value += value
+}
+
+// newDScanAnalysis returns the fleet composition of the d-scans in the parser result, or nil if there aren't any.
+// Prices are taken from the priced appraisal items.
+func (app *App) newDScanAnalysis(result parsers.ParserResult, items []AppraisalItem) *DScanAnalysis {
+	scans := findDScans(result)
+	if len(scans) == 0 {
+		return nil
+	}
+
+	prices := make(map[string]float64)
+	for _, item := range items {
+		prices[strings.ToUpper(item.Name)] = item.SingleRepresentativePrice()
+	}
+
+	gridDistance := viper.GetFloat64("dscan-grid-distance")
+	analysis := &DScanAnalysis{}
+	classes := make(map[string]*DScanCount)
+	groups := make(map[int64]*DScanCount)
+	hulls := make(map[int64]*DScanCount)
+	for _, scan := range scans {
+		for _, item := range scan.Items {
+			t, ok := app.TypeDB.GetType(item.Name)
+			if !ok || (t.CategoryID != ShipCategoryID && !structureCategoryIDs[t.CategoryID]) {
+				analysis.Ignored++
+				continue
+			}
+
+			group, ok := dscanGroups[t.GroupID]
+			if !ok {
+				group = dscanGroup{name: fmt.Sprintf("Group %d", t.GroupID), class: DScanClassOther}
+				if structureCategoryIDs[t.CategoryID] {
+					group.class = DScanClassStructure
+				}
+			}
+
+			onGrid := dscanDistanceKm(item) <= gridDistance
+			value := prices[strings.ToUpper(item.Name)]
+
+			if _, ok := classes[group.class]; !ok {
+				classes[group.class] = &DScanCount{Name: group.class, Class: group.class}
+			}
+			if _, ok := groups[t.GroupID]; !ok {
+				groups[t.GroupID] = &DScanCount{Name: group.name, GroupID: t.GroupID, Class: group.class}
+			}
+			if _, ok := hulls[t.ID]; !ok {
+				hulls[t.ID] = &DScanCount{Name: t.Name, TypeID: t.ID, GroupID: t.GroupID, Class: group.class}
+			}
+
+			classes[group.class].add(onGrid, value)
+			groups[t.GroupID].add(onGrid, value)
+			hulls[t.ID].add(onGrid, value)
+			if onGrid {
+				analysis.OnGrid.add(true, value)
+			} else {
+				analysis.OffGrid.add(false, value)
+			}
+		}
+	}
+
+	for _, count := range classes {
+		analysis.Classes = append(analysis.Classes, *count)
+	}
+	for _, count := range groups {
+		analysis.Groups = append(analysis.Groups, *count)
+	}
+	for _, count := range hulls {
+		analysis.Hulls = append(analysis.Hulls, *count)
+	}
+	sortDScanCounts(analysis.Classes)
+	sortDScanCounts(analysis.Groups)
+	sortDScanCounts(analysis.Hulls)
+	return analysis
+}
+
+// sortDScanCounts puts the most common hulls, groups or classes first
+func sortDScanCounts(counts []DScanCount) {
+	sort.Slice(counts, func(i, j int) bool {
+		if counts[i].Total() != counts[j].Total() {
+			return counts[i].Total() > counts[j].Total()
+		}
+		return counts[i].Name < counts[j].Name
+	})
+}
+
+// dscanDistanceKm returns the distance of a d-scan line in km. Things without a distance are further away than
+// anything with one.
+func dscanDistanceKm(item parsers.DScanItem) float64 {
+	switch item.DistanceUnit {
+	case "m":
+		return float64(item.Distance) / 1000
+	case "km":
+		return float64(item.Distance)
+	}
+	return math.Inf(1)
+}
+
+func findDScans(result parsers.ParserResult) []*parsers.DScan {
+	switch r := result.(type) {
+	case *parsers.DScan:
+		return []*parsers.DScan{r}
+	case *parsers.MultiParserResult:
+		var scans []*parsers.DScan
+		for _, subResult := range r.Results {
+			scans = append(scans, findDScans(subResult)...)
+		}
+		return scans
+	}
+	return nil
+}
@@ -0,0 +1,96 @@
+package evepraisal
+
+import (
+	"math"
+	"testing"
+
+	"github.com/evepraisal/go-evepraisal/parsers"
+	"github.com/evepraisal/go-evepraisal/typedb"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDScanDistanceKm(rt *testing.T) {
+	cases := []struct {
+		description string
+		item        parsers.DScanItem
+		expected    float64
+	}{
+		{"meters", parsers.DScanItem{Distance: 2500, DistanceUnit: "m"}, 2.5},
+		{"kilometers", parsers.DScanItem{Distance: 9500, DistanceUnit: "km"}, 9500},
+		{"astronomical units", parsers.DScanItem{Distance: 3, DistanceUnit: "AU"}, math.Inf(1)},
+		{"no distance", parsers.DScanItem{}, math.Inf(1)},
+	}
+
+	for _, c := range cases {
+		rt.Run(c.description, func(t *testing.T) {
+			assert.Equal(t, c.expected, dscanDistanceKm(c.item))
+		})
+	}
+}
+
+func TestDScanAnalysis(rt *testing.T) {
+	defer viper.Set("dscan-grid-distance", viper.Get("dscan-grid-distance"))
+	viper.Set("dscan-grid-distance", 10000.0)
+
+	app := &App{TypeDB: newFakeTypeDB(
+		typedb.EveType{ID: 11978, Name: "Scimitar", GroupID: 832, CategoryID: ShipCategoryID},
+		typedb.EveType{ID: 11202, Name: "Ares", GroupID: 831, CategoryID: ShipCategoryID},
+		typedb.EveType{ID: 23917, Name: "Wyvern", GroupID: 659, CategoryID: ShipCategoryID},
+		typedb.EveType{ID: 35832, Name: "Astrahus", GroupID: 1657, CategoryID: 65},
+		typedb.EveType{ID: 99999, Name: "Unlisted Hull", GroupID: 4242, CategoryID: ShipCategoryID},
+		typedb.EveType{ID: 99998, Name: "Unlisted Structure", GroupID: 4243, CategoryID: 65},
+		typedb.EveType{ID: 2488, Name: "Warrior II", GroupID: 100, CategoryID: 18},
+	)}
+
+	rt.Run("not a d-scan", func(t *testing.T) {
+		assert.Nil(t, app.newDScanAnalysis(&parsers.Listing{}, nil))
+	})
+
+	rt.Run("fleet composition", func(t *testing.T) {
+		result := &parsers.MultiParserResult{Results: []parsers.ParserResult{
+			&parsers.DScan{Items: []parsers.DScanItem{
+				{Name: "Scimitar", Distance: 500, DistanceUnit: "m"},
+				{Name: "Scimitar", Distance: 15000, DistanceUnit: "km"},
+				{Name: "Ares", Distance: 10000, DistanceUnit: "km"}, // right at the grid distance
+				{Name: "Wyvern", Distance: 2, DistanceUnit: "AU"},
+				{Name: "Astrahus"},
+				{Name: "Unlisted Hull", Distance: 1, DistanceUnit: "km"},
+				{Name: "Unlisted Structure", Distance: 1, DistanceUnit: "km"},
+				{Name: "Warrior II", Distance: 20, DistanceUnit: "km"},
+				{Name: "Not A Type", Distance: 20, DistanceUnit: "km"},
+			}},
+		}}
+		items := []AppraisalItem{
+			{Name: "Scimitar", Prices: Prices{Sell: PriceStats{Min: 200}}},
+			{Name: "Ares", Prices: Prices{Sell: PriceStats{Min: 50}}},
+			{Name: "Wyvern", Prices: Prices{Sell: PriceStats{Min: 20000}}},
+			{Name: "Astrahus", Prices: Prices{Sell: PriceStats{Min: 1000}}},
+		}
+
+		analysis := app.newDScanAnalysis(result, items)
+		assert.Equal(t, DScanCount{OnGrid: 4, Value: 250}, analysis.OnGrid)
+		assert.Equal(t, DScanCount{OffGrid: 3, Value: 21200}, analysis.OffGrid)
+		assert.Equal(t, int64(2), analysis.Ignored)
+
+		assert.Equal(t, []DScanCount{
+			{Name: DScanClassLogistics, Class: DScanClassLogistics, OnGrid: 1, OffGrid: 1, Value: 400},
+			{Name: DScanClassStructure, Class: DScanClassStructure, OnGrid: 1, OffGrid: 1, Value: 1000},
+			{Name: DScanClassCapital, Class: DScanClassCapital, OffGrid: 1, Value: 20000},
+			{Name: DScanClassOther, Class: DScanClassOther, OnGrid: 1},
+			{Name: DScanClassTackle, Class: DScanClassTackle, OnGrid: 1, Value: 50},
+		}, analysis.Classes)
+
+		assert.Equal(t, []DScanCount{
+			{Name: "Logistics", GroupID: 832, Class: DScanClassLogistics, OnGrid: 1, OffGrid: 1, Value: 400},
+			{Name: "Citadel", GroupID: 1657, Class: DScanClassStructure, OffGrid: 1, Value: 1000},
+			{Name: "Group 4242", GroupID: 4242, Class: DScanClassOther, OnGrid: 1},
+			{Name: "Group 4243", GroupID: 4243, Class: DScanClassStructure, OnGrid: 1},
+			{Name: "Interceptor", GroupID: 831, Class: DScanClassTackle, OnGrid: 1, Value: 50},
+			{Name: "Supercarrier", GroupID: 659, Class: DScanClassCapital, OffGrid: 1, Value: 20000},
+		}, analysis.Groups)
+
+		assert.Len(t, analysis.Hulls, 6)
+		assert.Equal(t, DScanCount{Name: "Scimitar", TypeID: 11978, GroupID: 832, Class: DScanClassLogistics, OnGrid: 1, OffGrid: 1, Value: 400}, analysis.Hulls[0])
+	})
+}
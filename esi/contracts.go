@@ -78,7 +78,7 @@ func (of *OauthFetcher) GetContractItems(characterID int64, contractID int64) (r
 func (of *OauthFetcher) GetContractStatus(user *evepraisal.User, appraisal *evepraisal.Appraisal) *ContractStatus {
 	contracts, err := of.GetContracts(user.CharacterID)
 	if err != nil {
-		fmt.Printf("CONTRACT ERROR: ", err)
+		fmt.Printf("CONTRACT ERROR: %s\n", err)
 		return &ContractStatus{of.BuybackTitle(user, appraisal.ID), "error", nil, []string{err.Error()}}
 	}
 
@@ -0,0 +1,53 @@
+package esi
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/sethgrid/pester"
+)
+
+type zkillboardKill struct {
+	KillmailID int64 `json:"killmail_id"`
+	ZKB        struct {
+		Hash string `json:"hash"`
+	} `json:"zkb"`
+}
+
+// KillmailFetcher fetches killmails from ESI. Links from zKillboard don't include the killmail hash, so it's
+// looked up through the zKillboard API first.
+type KillmailFetcher struct {
+	client        *pester.Client
+	baseURL       string
+	zkillboardURL string
+}
+
+func NewKillmailFetcher(baseURL string, zkillboardURL string, client *pester.Client) *KillmailFetcher {
+	return &KillmailFetcher{
+		client:        client,
+		baseURL:       baseURL,
+		zkillboardURL: zkillboardURL,
+	}
+}
+
+// FetchKillmail returns the ESI JSON for a killmail. It matches parsers.KillmailFetchFunc.
+func (f *KillmailFetcher) FetchKillmail(killmailID int64, hash string) ([]byte, error) {
+	if hash == "" {
+		var kills []zkillboardKill
+		err := fetchURL(f.client, fmt.Sprintf("%s/killID/%d/", f.zkillboardURL, killmailID), &kills)
+		if err != nil {
+			return nil, err
+		}
+		if len(kills) == 0 || kills[0].ZKB.Hash == "" {
+			return nil, fmt.Errorf("killmail %d not found on zkillboard", killmailID)
+		}
+		hash = kills[0].ZKB.Hash
+	}
+
+	var killmail json.RawMessage
+	err := fetchURL(f.client, fmt.Sprintf("%s/killmails/%d/%s/", f.baseURL, killmailID, hash), &killmail)
+	if err != nil {
+		return nil, err
+	}
+	return killmail, nil
+}
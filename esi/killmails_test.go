@@ -0,0 +1,49 @@
+package esi
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/sethgrid/pester"
+	"github.com/stretchr/testify/assert"
+)
+
+const killmailFixture = `{"killmail_id":72410059,"killmail_time":"2018-09-01T12:34:56Z","victim":{"ship_type_id":587,"items":[]}}`
+
+func newKillmailFixtureServer() *httptest.Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/killmails/72410059/abc123/", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(killmailFixture))
+	})
+	mux.HandleFunc("/zkb/killID/72410059/", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`[{"killmail_id":72410059,"zkb":{"hash":"abc123","totalValue":1000000}}]`))
+	})
+	mux.HandleFunc("/zkb/killID/1/", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`[]`))
+	})
+	return httptest.NewServer(mux)
+}
+
+func TestFetchKillmail(t *testing.T) {
+	server := newKillmailFixtureServer()
+	defer server.Close()
+
+	client := pester.New()
+	client.MaxRetries = 1
+	fetcher := NewKillmailFetcher(server.URL, server.URL+"/zkb", client)
+
+	raw, err := fetcher.FetchKillmail(72410059, "abc123")
+	assert.NoError(t, err)
+	assert.JSONEq(t, killmailFixture, string(raw))
+
+	raw, err = fetcher.FetchKillmail(72410059, "")
+	assert.NoError(t, err)
+	assert.JSONEq(t, killmailFixture, string(raw))
+
+	_, err = fetcher.FetchKillmail(1, "")
+	assert.Error(t, err)
+
+	_, err = fetcher.FetchKillmail(72410059, "wrong")
+	assert.Error(t, err)
+}
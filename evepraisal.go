@@ -14,6 +14,7 @@ type App struct {
 	AppraisalDB         AppraisalDB
 	CacheDB             CacheDB
 	TypeDB              typedb.TypeDB
+	TypeDBArchive       *typedb.Archive
 	PriceDB             PriceDB
 	Parser              parsers.Parser
 	WebContext          WebContext
@@ -36,11 +37,15 @@ type AppraisalDB interface {
 	LatestAppraisalsByUser(user User, count int, kind string, after string) ([]Appraisal, error)
 	TotalAppraisals() (int64, error)
 	DeleteAppraisal(appraisalID string) error
+	ForEachAppraisal(since time.Time, kind string, fn func(appraisal *Appraisal) error) error
+	PutAppraisals(appraisals []*Appraisal) (errs []error, err error)
 	Close() error
 }
 
 var (
-	AppraisalNotFound = errors.New("Appraisal not found")
+	AppraisalNotFound  = errors.New("Appraisal not found")
+	AppraisalExists    = errors.New("Appraisal already exists")
+	InvalidAppraisalID = errors.New("Invalid appraisal ID")
 )
 
 type PriceDB interface {
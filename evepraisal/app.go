@@ -12,6 +12,7 @@ import (
 	"time"
 
 	"github.com/evepraisal/go-evepraisal"
+	"github.com/evepraisal/go-evepraisal/backup"
 	"github.com/evepraisal/go-evepraisal/bolt"
 	"github.com/evepraisal/go-evepraisal/esi"
 	"github.com/evepraisal/go-evepraisal/management"
@@ -29,12 +30,15 @@ import (
 	"golang.org/x/oauth2"
 )
 
+// typeDBCloseDelay is how long typedbs that fall out of the archive stay open for requests that still use them
+const typeDBCloseDelay = time.Minute
+
 func appMain() {
 	stop := make(chan os.Signal, 1)
 	signal.Notify(stop, os.Interrupt)
 
 	log.Println("Starting price DB")
-	priceDB, err := bolt.NewPriceDB(filepath.Join(viper.GetString("db_path"), "prices"))
+	priceDB, err := bolt.NewPriceDB(filepath.Join(viper.GetString("db_path"), "prices"), true)
 	if err != nil {
 		log.Fatalf("Couldn't start price database: %s", err)
 	}
@@ -45,7 +49,13 @@ func appMain() {
 		}
 	}()
 
-	httpCache, err := bolt.NewHTTPCache(filepath.Join(viper.GetString("db_path"), "httpcache"))
+	httpCache, err := bolt.NewHTTPCache(
+		filepath.Join(viper.GetString("db_path"), "httpcache"),
+		bolt.HTTPCacheOptions{
+			TTL:           viper.GetDuration("httpcache_ttl"),
+			MaxSize:       viper.GetInt64("httpcache_max-size"),
+			SweepInterval: viper.GetDuration("httpcache_sweep-interval"),
+		})
 	if err != nil {
 		log.Fatalf("Couldn't start httpCache: %s", err)
 	}
@@ -81,6 +91,17 @@ func appMain() {
 		}
 	}()
 
+	// Killmails are fetched while an appraisal is being made, so they get a client that gives up quickly
+	var fetchKillmail parsers.KillmailFetchFunc
+	if viper.GetBool("killmail_fetch") {
+		killmailHTTPClient := pester.New()
+		killmailHTTPClient.Transport = httpcache.NewTransport(httpCache)
+		killmailHTTPClient.Concurrency = 1
+		killmailHTTPClient.Timeout = viper.GetDuration("killmail_fetch-timeout")
+		killmailHTTPClient.MaxRetries = 1
+		fetchKillmail = esi.NewKillmailFetcher(viper.GetString("esi_baseurl"), viper.GetString("zkillboard_baseurl"), killmailHTTPClient).FetchKillmail
+	}
+
 	log.Println("Starting appraisal DB")
 	appraisalDB, err := bolt.NewAppraisalDB(filepath.Join(viper.GetString("db_path"), "appraisals"))
 	if err != nil {
@@ -117,34 +138,30 @@ func appMain() {
 		staticdumpHTTPClient.Transport = NewRoundTripper(newRelicApplication, nil)
 	}
 
+	app.TypeDBArchive = typedb.NewArchive(viper.GetInt("typedb_keep"), typeDBCloseDelay)
+	typeDBPaths, err := staticdump.TypeDBPaths(viper.GetString("db_path"))
+	if err != nil {
+		log.Fatalf("Couldn't find previous typedbs: %s", err)
+	}
+	for _, typeDBPath := range typeDBPaths {
+		typeDB, err := bolt.NewTypeDB(typeDBPath, false)
+		if err != nil {
+			log.Printf("WARNING: Unable to open previous typedb %s: %s", typeDBPath, err)
+			continue
+		}
+
+		if !app.TypeDBArchive.AddOlder(typeDB) {
+			typeDB.Close()
+			break
+		}
+		log.Printf("Opened previous typedb %s", typeDB.Version())
+	}
+
 	staticFetcher, err := staticdump.NewStaticFetcher(staticdumpHTTPClient, viper.GetString("db_path"), func(typeDB typedb.TypeDB) {
-		oldTypeDB := app.TypeDB
 		app.TypeDB = typeDB
-		app.Parser = evepraisal.NewContextMultiParser(
-			typeDB,
-			[]parsers.Parser{
-				parsers.ParseKillmail,
-				parsers.ParseEFT,
-				parsers.ParseFitting,
-				parsers.ParseLootHistory,
-				parsers.ParsePI,
-				parsers.ParseViewContents,
-				parsers.ParseWallet,
-				parsers.ParseSurveyScan,
-				parsers.ParseContract,
-				parsers.NewContextListingParser(typeDB),
-				parsers.ParseAssets,
-				parsers.ParseIndustry,
-				parsers.ParseCargoScan,
-				parsers.ParseDScan,
-				parsers.NewHeuristicParser(typeDB),
-			})
-
-		if oldTypeDB != nil {
-			log.Println("closing old typedb")
-			oldTypeDB.Close()
-			log.Println("closed old typedb")
-		}
+		app.Parser = newParser(typeDB, fetchKillmail)
+		// Swap the new typedb in before archiving it, since that closes the ones that fall out of the archive
+		app.TypeDBArchive.Add(typeDB)
 	})
 	if err != nil {
 		log.Fatalf("Couldn't start static fetcher: %s", err)
@@ -155,11 +172,9 @@ func appMain() {
 			log.Fatalf("Problem closing static fetcher: %s", err)
 		}
 
-		if app.TypeDB != nil {
-			err = app.TypeDB.Close()
-			if err != nil {
-				log.Fatalf("Problem closing typeDB: %s", err)
-			}
+		err = app.TypeDBArchive.Close()
+		if err != nil {
+			log.Fatalf("Problem closing typeDBs: %s", err)
 		}
 	}()
 
@@ -206,10 +221,37 @@ func appMain() {
 
 	startEnvironmentWatchers(app)
 
+	backupPath := viper.GetString("backup_path")
+	if backupPath == "" {
+		backupPath = filepath.Join(viper.GetString("db_path"), "backups")
+	}
+	backupManager, err := backup.NewManager(backupPath, viper.GetDuration("backup_interval"), viper.GetInt("backup_keep"), func() map[string]backup.Snapshotter {
+		sources := map[string]backup.Snapshotter{"httpcache": httpCache}
+		if s, ok := app.AppraisalDB.(backup.Snapshotter); ok {
+			sources["appraisals"] = s
+		}
+		if s, ok := app.PriceDB.(backup.Snapshotter); ok {
+			sources["prices"] = s
+		}
+		if s, ok := app.TypeDB.(backup.Snapshotter); ok {
+			sources["typedb"] = s
+		}
+		return sources
+	})
+	if err != nil {
+		log.Fatalf("Couldn't start backup manager: %s", err)
+	}
+	defer func() {
+		err := backupManager.Close()
+		if err != nil {
+			log.Fatalf("Problem closing backup manager: %s", err)
+		}
+	}()
+
 	log.Printf("Starting Management HTTP server (%s)", viper.GetString("management_addr"))
 	mgmtServer := &http.Server{
 		Addr:    viper.GetString("management_addr"),
-		Handler: management.HTTPHandler(app),
+		Handler: management.HTTPHandler(app, backupManager),
 	}
 	defer mgmtServer.Close()
 	go func() {
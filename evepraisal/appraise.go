@@ -0,0 +1,193 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/dustin/go-humanize"
+	"github.com/evepraisal/go-evepraisal"
+	"github.com/evepraisal/go-evepraisal/bolt"
+	"github.com/evepraisal/go-evepraisal/staticdump"
+	"github.com/evepraisal/go-evepraisal/web"
+	"github.com/spf13/viper"
+)
+
+func appraiseMain() {
+	appraiseCmd := flag.NewFlagSet("appraise", flag.ExitOnError)
+	market := appraiseCmd.String("market", "jita", "market to price the items in")
+	format := appraiseCmd.String("format", "table", "output format: table, json or csv (unparsed lines go to stderr)")
+	appraiseCmd.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Usage: evepraisal appraise [options] [file ...]")
+		fmt.Fprintln(os.Stderr, "Appraises the files, or stdin if there are none, as a single appraisal.")
+		appraiseCmd.PrintDefaults()
+	}
+	err := appraiseCmd.Parse(os.Args[2:])
+	if err != nil || appraiseCmd.Parsed() == false {
+		appraiseCmd.Usage()
+		os.Exit(2)
+	}
+
+	var write func(w io.Writer, appraisal *evepraisal.Appraisal) error
+	switch *format {
+	case "table":
+		write = writeAppraisalTable
+	case "json":
+		write = writeAppraisalJSON
+	case "csv":
+		write = writeAppraisalCSV
+	default:
+		appraiseCmd.Usage()
+		log.Fatalf("%q is not a valid format, use table, json or csv", *format)
+	}
+
+	if !web.IsSelectableMarket(strings.ToLower(*market)) {
+		appraiseCmd.Usage()
+		log.Fatalf("%q is not a market that appraisals can be made in", *market)
+	}
+
+	text, err := readAppraiseInput(appraiseCmd.Args())
+	if err != nil {
+		log.Fatalf("Unable to read input: %s", err)
+	}
+
+	typeDBPaths, err := staticdump.TypeDBPaths(viper.GetString("db_path"))
+	if err != nil {
+		log.Fatalf("Couldn't find typedbs: %s", err)
+	}
+	if len(typeDBPaths) == 0 {
+		log.Fatalf("No typedb found in %s, start the server once to download one", viper.GetString("db_path"))
+	}
+	typeDB, err := bolt.NewTypeDB(typeDBPaths[0], false)
+	if err != nil {
+		log.Fatalf("Couldn't open typedb %s: %s", typeDBPaths[0], err)
+	}
+	defer typeDB.Close()
+
+	priceDB, err := bolt.NewPriceDB(filepath.Join(viper.GetString("db_path"), "prices"), false)
+	if err != nil {
+		log.Fatalf("Couldn't open price database (is the server running?): %s", err)
+	}
+	defer priceDB.Close()
+
+	app := &evepraisal.App{
+		TypeDB:  typeDB,
+		PriceDB: priceDB,
+		Parser:  newParser(typeDB, nil),
+	}
+
+	appraisal, err := app.StringToAppraisal(strings.ToLower(*market), text)
+	if err == evepraisal.ErrNoValidLinesFound {
+		writeUnparsed(os.Stderr, appraisal)
+		log.Println("Nothing could be parsed")
+		typeDB.Close()
+		priceDB.Close()
+		os.Exit(1)
+	} else if err != nil {
+		log.Fatalf("Unable to appraise: %s", err)
+	}
+
+	// Which parser took each line is only kept by the web app when asked for, so leave it out here as well
+	appraisal.Parse = nil
+
+	err = write(os.Stdout, appraisal)
+	if err != nil {
+		log.Fatalf("Unable to write appraisal: %s", err)
+	}
+}
+
+// readAppraiseInput reads the files one after the other, or stdin if there are none. A file named - is stdin.
+func readAppraiseInput(filenames []string) (string, error) {
+	if len(filenames) == 0 {
+		filenames = []string{"-"}
+	}
+
+	var texts []string
+	for _, filename := range filenames {
+		var (
+			data []byte
+			err  error
+		)
+		if filename == "-" {
+			data, err = ioutil.ReadAll(os.Stdin)
+		} else {
+			data, err = ioutil.ReadFile(filename)
+		}
+		if err != nil {
+			return "", err
+		}
+		texts = append(texts, strings.TrimRight(string(data), "\r\n"))
+	}
+	return strings.Join(texts, "\n"), nil
+}
+
+func formatISK(f float64) string {
+	return humanize.FormatFloat("#,###.##", f)
+}
+
+func writeAppraisalTable(w io.Writer, appraisal *evepraisal.Appraisal) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintf(tw, "Quantity\tName\tBuy Total\tSell Total\tBuyback\t\n")
+	for _, item := range appraisal.Original.Items {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t\n",
+			humanize.Comma(item.Quantity),
+			item.DisplayName(),
+			formatISK(item.BuyTotal()),
+			formatISK(item.SellTotal()),
+			formatISK(item.Buyback.Totals.Buy))
+	}
+	fmt.Fprintf(tw, "\tTotal (%s m3)\t%s\t%s\t%s\t\n",
+		humanize.FormatFloat("#,###.##", appraisal.Original.Totals.Volume),
+		formatISK(appraisal.Original.Totals.Buy),
+		formatISK(appraisal.Original.Totals.Sell),
+		formatISK(appraisal.BuybackOffer()))
+	err := tw.Flush()
+	if err != nil {
+		return err
+	}
+
+	writeUnparsed(os.Stderr, appraisal)
+	return nil
+}
+
+func writeAppraisalJSON(w io.Writer, appraisal *evepraisal.Appraisal) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(appraisal)
+}
+
+func writeAppraisalCSV(w io.Writer, appraisal *evepraisal.Appraisal) error {
+	exporter, ok := evepraisal.FindExporter("csv")
+	if !ok {
+		return fmt.Errorf("the csv exporter isn't registered")
+	}
+
+	writeUnparsed(os.Stderr, appraisal)
+	return exporter.Export(w, []evepraisal.Appraisal{*appraisal}, nil)
+}
+
+// writeUnparsed lists the lines of the input that couldn't be used, in order
+func writeUnparsed(w io.Writer, appraisal *evepraisal.Appraisal) {
+	if len(appraisal.Unparsed) == 0 {
+		return
+	}
+
+	lineNumbers := make([]int, 0, len(appraisal.Unparsed))
+	for lineNumber := range appraisal.Unparsed {
+		lineNumbers = append(lineNumbers, lineNumber)
+	}
+	sort.Ints(lineNumbers)
+
+	fmt.Fprintf(w, "\nUnparsed lines:\n")
+	for _, lineNumber := range lineNumbers {
+		fmt.Fprintf(w, "%d\t%s\n", lineNumber+1, appraisal.Unparsed[lineNumber])
+	}
+}
@@ -17,7 +17,20 @@ func init() {
 	viper.SetDefault("https_domain-whitelist", []string{"evepraisal.com"})
 	viper.SetDefault("letsencrypt_email", "")
 	viper.SetDefault("db_path", "db/")
+	viper.SetDefault("typedb_keep", 3)
+	viper.SetDefault("backup_path", "")
+	viper.SetDefault("backup_interval", "24h")
+	viper.SetDefault("backup_keep", 7)
+	viper.SetDefault("httpcache_ttl", "168h")
+	viper.SetDefault("httpcache_max-size", 512*1024*1024)
+	viper.SetDefault("httpcache_sweep-interval", "10m")
 	viper.SetDefault("esi_baseurl", "https://esi.tech.ccp.is/latest")
+	viper.SetDefault("zkillboard_baseurl", "https://zkillboard.com/api")
+	viper.SetDefault("killmail_fetch", false)
+	viper.SetDefault("killmail_fetch-timeout", "2s")
+	viper.SetDefault("parsers_enabled", []string{})
+	viper.SetDefault("parsers_disabled", []string{})
+	viper.SetDefault("parsers_priorities", map[string]int{})
 	viper.SetDefault("newrelic_app-name", "Evepraisal")
 	viper.SetDefault("newrelic_license-key", "")
 	viper.SetDefault("management_addr", "127.0.0.1:8090")
@@ -34,5 +47,12 @@ func init() {
 	viper.SetDefault("buyback-max-volume", 80000)
 	viper.SetDefault("buyback-base-adjustment", 85.0)
 
+	viper.SetDefault("market-order-tolerance", 5.0)
+	viper.SetDefault("dscan-grid-distance", 10000.0)
+	viper.SetDefault("pi-customs-tax", 10.0)
+	viper.SetDefault("survey-ship", "")
+	viper.SetDefault("survey-yield", 1000.0)
+	viper.SetDefault("survey-cycle-time", 60.0)
+
 	viper.SetDefault("adjustments", map[string]float64{})
 }
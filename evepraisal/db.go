@@ -0,0 +1,75 @@
+package main
+
+import (
+	"flag"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/evepraisal/go-evepraisal/bolt"
+	"github.com/spf13/viper"
+)
+
+func dbMain() {
+	if len(os.Args) < 3 {
+		log.Fatalln("Usage: evepraisal db compact|check [options]")
+	}
+
+	dbCmd := flag.NewFlagSet("db "+os.Args[2], flag.ExitOnError)
+	dbNames := dbCmd.String("dbs", "appraisals,prices,httpcache", "comma-separated databases (in db_path) to work on")
+	repair := dbCmd.Bool("repair", false, "remove orphaned index entries (check only)")
+	err := dbCmd.Parse(os.Args[3:])
+	if err != nil || dbCmd.Parsed() == false {
+		dbCmd.PrintDefaults()
+		os.Exit(2)
+	}
+
+	failed := false
+	for _, name := range strings.Split(*dbNames, ",") {
+		filename := filepath.Join(viper.GetString("db_path"), name)
+		switch os.Args[2] {
+		case "compact":
+			log.Printf("Start compacting %s", filename)
+			before, after, err := bolt.Compact(filename)
+			if err != nil {
+				log.Printf("ERROR: Unable to compact %s: %s", filename, err)
+				failed = true
+				continue
+			}
+			log.Printf("Done compacting %s (%d -> %d bytes)", filename, before, after)
+		case "check":
+			log.Printf("Start checking %s", filename)
+			report, err := bolt.Check(filename, *repair)
+			if err != nil {
+				log.Printf("ERROR: Unable to check %s: %s", filename, err)
+				failed = true
+				continue
+			}
+
+			for _, checkErr := range report.Errors {
+				log.Printf("ERROR: %s: %s", filename, checkErr)
+				failed = true
+			}
+
+			for bucketName, count := range report.Orphans {
+				if count == 0 {
+					continue
+				}
+				if *repair {
+					log.Printf("Removed %d orphaned keys from %s", count, bucketName)
+				} else {
+					log.Printf("Found %d orphaned keys in %s (use -repair to remove them)", count, bucketName)
+					failed = true
+				}
+			}
+			log.Printf("Done checking %s", filename)
+		default:
+			log.Fatalf("%q is not a valid db command, use compact or check", os.Args[2])
+		}
+	}
+
+	if failed {
+		os.Exit(1)
+	}
+}
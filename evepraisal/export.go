@@ -0,0 +1,60 @@
+package main
+
+import (
+	"flag"
+	"io"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+
+	"github.com/spf13/viper"
+)
+
+func exportMain() {
+	exportCmd := flag.NewFlagSet("export", flag.ExitOnError)
+	since := exportCmd.String("since", "", "only export appraisals created after this time (unix timestamp or RFC3339)")
+	kind := exportCmd.String("kind", "", "only export appraisals of this kind")
+	output := exportCmd.String("output", "-", "file to write JSON lines to, - for stdout")
+	err := exportCmd.Parse(os.Args[2:])
+	if err != nil || exportCmd.Parsed() == false {
+		exportCmd.PrintDefaults()
+		os.Exit(2)
+	}
+
+	params := url.Values{}
+	if *since != "" {
+		params.Set("since", *since)
+	}
+	if *kind != "" {
+		params.Set("kind", *kind)
+	}
+
+	resp, err := http.Get("http://" + viper.GetString("management_addr") + "/export?" + params.Encode())
+	if err != nil {
+		log.Fatalf("Error requesting export: %s", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		body, _ := ioutil.ReadAll(resp.Body)
+		log.Fatalf("ERROR: %s: %s", resp.Status, string(body))
+	}
+
+	var w io.Writer = os.Stdout
+	if *output != "-" {
+		f, err := os.Create(*output)
+		if err != nil {
+			log.Fatalf("Unable to create output file: %s", err)
+		}
+		defer f.Close()
+		w = f
+	}
+
+	n, err := io.Copy(w, resp.Body)
+	if err != nil {
+		log.Fatalf("Error while exporting: %s", err)
+	}
+	log.Printf("Done exporting (%d bytes)", n)
+}
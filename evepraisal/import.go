@@ -0,0 +1,63 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"io"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"os"
+	"strconv"
+
+	"github.com/evepraisal/go-evepraisal/management"
+	"github.com/spf13/viper"
+)
+
+func importMain() {
+	importCmd := flag.NewFlagSet("import", flag.ExitOnError)
+	filename := importCmd.String("file", "-", "JSON lines file to import appraisals from, - for stdin")
+	batchSize := importCmd.Int("batch", 500, "number of appraisals to store per transaction")
+	err := importCmd.Parse(os.Args[2:])
+	if err != nil || importCmd.Parsed() == false {
+		importCmd.PrintDefaults()
+		os.Exit(2)
+	}
+
+	var r io.Reader = os.Stdin
+	if *filename != "-" {
+		f, err := os.Open(*filename)
+		if err != nil {
+			importCmd.PrintDefaults()
+			log.Fatalf("Unable to open file: %s", err)
+		}
+		defer f.Close()
+		r = f
+	}
+
+	url := "http://" + viper.GetString("management_addr") + "/import?batch=" + strconv.Itoa(*batchSize)
+	resp, err := http.Post(url, "application/x-ndjson", r)
+	if err != nil {
+		log.Fatalf("Error while importing: %s", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		body, _ := ioutil.ReadAll(resp.Body)
+		log.Fatalf("ERROR: %s: %s", resp.Status, string(body))
+	}
+
+	var report management.ImportReport
+	err = json.NewDecoder(resp.Body).Decode(&report)
+	if err != nil {
+		log.Fatalf("Unable to read import report: %s", err)
+	}
+
+	for _, importErr := range report.Errors {
+		log.Printf("Line %d (%s): %s", importErr.Line, importErr.ID, importErr.Error)
+	}
+	log.Printf("Done importing: %d imported, %d duplicates, %d failed", report.Imported, report.Duplicates, report.Failed)
+	if report.Failed > 0 {
+		os.Exit(1)
+	}
+}
@@ -52,6 +52,16 @@ func main() {
 		switch os.Args[1] {
 		case "restore":
 			restoreMain()
+		case "export":
+			exportMain()
+		case "import":
+			importMain()
+		case "restore-db":
+			restoreDBMain()
+		case "db":
+			dbMain()
+		case "appraise":
+			appraiseMain()
 		default:
 			fmt.Printf("%q is not valid command.\n", os.Args[1])
 			os.Exit(2)
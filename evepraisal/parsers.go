@@ -0,0 +1,45 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+
+	"github.com/evepraisal/go-evepraisal"
+	"github.com/evepraisal/go-evepraisal/parsers"
+	"github.com/evepraisal/go-evepraisal/typedb"
+	"github.com/spf13/viper"
+)
+
+// parserConfig reads which parsers are used, and in what order, from the parsers_* settings
+func parserConfig() (parsers.ParserConfig, error) {
+	config := parsers.ParserConfig{
+		Enabled:    viper.GetStringSlice("parsers_enabled"),
+		Disabled:   viper.GetStringSlice("parsers_disabled"),
+		Priorities: make(map[string]int),
+	}
+	for name, value := range viper.GetStringMapString("parsers_priorities") {
+		priority, err := strconv.Atoi(strings.TrimSpace(value))
+		if err != nil {
+			return config, fmt.Errorf("invalid priority for parser %s: %s", name, value)
+		}
+		config.Priorities[name] = priority
+	}
+	return config, nil
+}
+
+// newParser builds the parser that appraisals are made with from the registered parsers. Everything that parses
+// pastes uses it, so the web app and the other commands can't disagree about what a paste is.
+func newParser(typeDB typedb.TypeDB, fetchKillmail parsers.KillmailFetchFunc) parsers.Parser {
+	config, err := parserConfig()
+	if err != nil {
+		log.Fatalf("Bad parser config: %s", err)
+	}
+
+	stages, err := parsers.NewParserStages(parsers.ParserDeps{TypeDB: typeDB, FetchKillmail: fetchKillmail}, config)
+	if err != nil {
+		log.Fatalf("Bad parser config: %s", err)
+	}
+	return evepraisal.NewContextMultiParser(typeDB, stages)
+}
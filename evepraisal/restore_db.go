@@ -0,0 +1,69 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"log"
+	"os"
+	"path/filepath"
+
+	"github.com/evepraisal/go-evepraisal/backup"
+	"github.com/spf13/viper"
+)
+
+func restoreDBMain() {
+	restoreDBCmd := flag.NewFlagSet("restore-db", flag.ExitOnError)
+	snapshot := restoreDBCmd.String("snapshot", "", "snapshot file to restore")
+	target := restoreDBCmd.String("target", "", "database file to replace (defaults to the snapshot's database in db_path)")
+	verifyOnly := restoreDBCmd.Bool("verify", false, "only verify the snapshot, don't swap it in")
+	err := restoreDBCmd.Parse(os.Args[2:])
+	if err != nil || restoreDBCmd.Parsed() == false {
+		restoreDBCmd.PrintDefaults()
+		os.Exit(2)
+	}
+
+	if *snapshot == "" {
+		restoreDBCmd.PrintDefaults()
+		log.Fatalln("The -snapshot option is required")
+	}
+
+	info, ok := backup.ParseFilename(*snapshot)
+	if !ok {
+		log.Fatalf("%s does not look like a snapshot file", *snapshot)
+	}
+
+	if *verifyOnly {
+		tmpFilename := filepath.Join(os.TempDir(), filepath.Base(*snapshot)+".verify")
+		defer os.Remove(tmpFilename)
+		err = backup.Restore(*snapshot, tmpFilename)
+		if err != nil {
+			log.Fatalf("Snapshot is not valid: %s", err)
+		}
+		log.Printf("Snapshot %s is valid", *snapshot)
+		return
+	}
+
+	*target, err = restoreTarget(info, *target, viper.GetString("db_path"))
+	if err != nil {
+		log.Fatalln(err)
+	}
+
+	log.Printf("Restoring %s to %s", *snapshot, *target)
+	err = backup.Restore(*snapshot, *target)
+	if err != nil {
+		log.Fatalf("Unable to restore snapshot: %s", err)
+	}
+	log.Printf("Done restoring %s, the previous database was moved to %s.pre-restore", *target, *target)
+}
+
+// restoreTarget returns the database file a snapshot should replace. Typedbs are versioned, so their
+// target can't be derived from the snapshot name.
+func restoreTarget(info backup.Backup, target string, dbPath string) (string, error) {
+	if target != "" {
+		return target, nil
+	}
+	if info.Name == "typedb" {
+		return "", errors.New("The -target option is required when restoring a typedb snapshot")
+	}
+	return filepath.Join(dbPath, info.Name), nil
+}
@@ -0,0 +1,30 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/evepraisal/go-evepraisal/backup"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRestoreTarget(t *testing.T) {
+	info, ok := backup.ParseFilename("/backups/appraisals-20170102T030405Z.bolt.gz")
+	assert.True(t, ok)
+
+	target, err := restoreTarget(info, "", "/var/db")
+	assert.NoError(t, err)
+	assert.Equal(t, "/var/db/appraisals", target)
+
+	target, err = restoreTarget(info, "/tmp/other", "/var/db")
+	assert.NoError(t, err)
+	assert.Equal(t, "/tmp/other", target)
+
+	info, ok = backup.ParseFilename("typedb-20170102T030405Z.bolt.gz")
+	assert.True(t, ok)
+	_, err = restoreTarget(info, "", "/var/db")
+	assert.Error(t, err)
+
+	target, err = restoreTarget(info, "/var/db/types-1", "/var/db")
+	assert.NoError(t, err)
+	assert.Equal(t, "/var/db/types-1", target)
+}
@@ -0,0 +1,265 @@
+package evepraisal
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/evepraisal/go-evepraisal/parsers"
+	"github.com/evepraisal/go-evepraisal/spreadsheet"
+	"github.com/evepraisal/go-evepraisal/typedb"
+)
+
+var (
+	ErrNotExportable = fmt.Errorf("The appraisal can't be exported in this format")
+)
+
+// Exporter writes appraisals in a format that can be downloaded. Suffix is the extension that selects it, like
+// "csv" for /a/[id].csv. Exporters that are Attachments are downloaded as files instead of being shown. The type
+// database may be nil.
+type Exporter struct {
+	Suffix      string
+	ContentType string
+	Attachment  bool
+	Export      func(w io.Writer, appraisals []Appraisal, typeDB typedb.TypeDB) error
+}
+
+var exporters []Exporter
+
+// RegisterExporter makes an exporter available for appraisals and lists of appraisals
+func RegisterExporter(exporter Exporter) {
+	exporters = append(exporters, exporter)
+}
+
+// Exporters returns all registered exporters
+func Exporters() []Exporter {
+	return exporters
+}
+
+// FindExporter returns the exporter for the given suffix
+func FindExporter(suffix string) (Exporter, bool) {
+	for _, exporter := range exporters {
+		if exporter.Suffix == suffix {
+			return exporter, true
+		}
+	}
+	return Exporter{}, false
+}
+
+func init() {
+	RegisterExporter(Exporter{Suffix: "csv", ContentType: "text/csv; charset=utf-8", Attachment: true, Export: exportSpreadsheet(spreadsheet.CSV)})
+	RegisterExporter(Exporter{Suffix: "xlsx", ContentType: "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet", Attachment: true, Export: exportSpreadsheet(spreadsheet.XLSX)})
+	RegisterExporter(Exporter{Suffix: "eft", ContentType: "text/plain; charset=utf-8", Export: exportEFT})
+	RegisterExporter(Exporter{Suffix: "multibuy", ContentType: "text/plain; charset=utf-8", Export: exportMultibuy})
+	RegisterExporter(Exporter{Suffix: "quickbar", ContentType: "text/plain; charset=utf-8", Export: exportQuickbar})
+}
+
+// AppraisalRows returns a header row followed by a row for every item of the appraisals
+func AppraisalRows(appraisals []Appraisal) [][]string {
+	rows := [][]string{{
+		"Appraisal", "Type ID", "Name", "Quantity", "Volume",
+		"Buy", "Buy Total", "Sell", "Sell Total",
+		"Adjustment", "Buyback Buy Total", "Buyback Items",
+	}}
+
+	isk := func(f float64) string { return strconv.FormatFloat(f, 'f', 2, 64) }
+	for _, appraisal := range appraisals {
+		for _, item := range appraisal.Original.Items {
+			var buybackItems []string
+			for _, buybackItem := range item.Buyback.Items {
+				buybackItems = append(buybackItems, fmt.Sprintf("%s x%d", buybackItem.DisplayName(), buybackItem.Quantity))
+			}
+
+			rows = append(rows, []string{
+				appraisal.ID,
+				strconv.FormatInt(item.TypeID, 10),
+				item.DisplayName(),
+				strconv.FormatInt(item.Quantity, 10),
+				strconv.FormatFloat(item.TypeVolume*float64(item.Quantity), 'f', -1, 64),
+				isk(item.EffectiveAdjustment() * item.Prices.Buy.Max),
+				isk(item.BuyTotal()),
+				isk(item.EffectiveAdjustment() * item.Prices.Sell.Min),
+				isk(item.SellTotal()),
+				strconv.FormatFloat(item.EffectiveAdjustment()*100, 'f', -1, 64),
+				isk(item.Buyback.Totals.Buy),
+				strings.Join(buybackItems, ", "),
+			})
+		}
+	}
+	return rows
+}
+
+func exportSpreadsheet(format spreadsheet.Format) func(w io.Writer, appraisals []Appraisal, typeDB typedb.TypeDB) error {
+	return func(w io.Writer, appraisals []Appraisal, typeDB typedb.TypeDB) error {
+		return spreadsheet.Write(w, format, AppraisalRows(appraisals))
+	}
+}
+
+// Categories of items that are fitted to a ship, one per slot, instead of being carried in a bay
+var fittedCategoryIDs = map[int64]bool{
+	7:  true, // Module
+	32: true, // Subsystem
+}
+
+// AppraisalToEFT returns the fits of the appraisal in EFT format. Only appraisals with groups that have a ship,
+// like the ones made from fittings, can be written as EFT. Modules get a line each and everything else is
+// written as cargo. Without a type database, items with a quantity of one are taken to be modules.
+func AppraisalToEFT(appraisal *Appraisal, typeDB typedb.TypeDB) (string, error) {
+	var buffer bytes.Buffer
+	for _, group := range appraisal.Groups {
+		if group.Ship == "" {
+			continue
+		}
+
+		if buffer.Len() > 0 {
+			buffer.WriteString("\n")
+		}
+		fmt.Fprintf(&buffer, "[%s, %s]\n", group.Ship, group.Name)
+
+		var cargo []AppraisalItem
+		shipSkipped := false
+		for _, item := range group.Items {
+			if !shipSkipped && strings.EqualFold(item.Name, group.Ship) {
+				shipSkipped = true
+				if item.Quantity <= 1 {
+					continue
+				}
+				item.Quantity--
+			}
+
+			fitted := item.Quantity == 1
+			if typeDB != nil {
+				if t, ok := typeDB.GetTypeByID(item.TypeID); ok {
+					fitted = fittedCategoryIDs[t.CategoryID]
+				}
+			}
+
+			if !fitted {
+				cargo = append(cargo, item)
+				continue
+			}
+			for i := int64(0); i < item.Quantity; i++ {
+				fmt.Fprintf(&buffer, "%s\n", item.DisplayName())
+			}
+		}
+
+		if len(cargo) > 0 {
+			buffer.WriteString("\n")
+		}
+		for _, item := range cargo {
+			fmt.Fprintf(&buffer, "%s x%d\n", item.DisplayName(), item.Quantity)
+		}
+	}
+
+	if buffer.Len() == 0 {
+		return "", ErrNotExportable
+	}
+	return buffer.String(), nil
+}
+
+func exportEFT(w io.Writer, appraisals []Appraisal, typeDB typedb.TypeDB) error {
+	var fits []string
+	for i := range appraisals {
+		fit, err := AppraisalToEFT(&appraisals[i], typeDB)
+		if err == ErrNotExportable {
+			continue
+		} else if err != nil {
+			return err
+		}
+		fits = append(fits, fit)
+	}
+
+	if len(fits) == 0 {
+		return ErrNotExportable
+	}
+	_, err := io.WriteString(w, strings.Join(fits, "\n"))
+	return err
+}
+
+// AppraisalToMultibuy returns the items of the appraisal as "Name quantity" lines, which can be imported into the
+// multibuy window
+func AppraisalToMultibuy(appraisal *Appraisal) string {
+	var names []string
+	quantities := make(map[string]int64)
+	for _, item := range appraisal.Original.Items {
+		name := item.DisplayName()
+		if _, ok := quantities[name]; !ok {
+			names = append(names, name)
+		}
+		quantities[name] += item.Quantity
+	}
+
+	var buffer bytes.Buffer
+	for _, name := range names {
+		if quantities[name] <= 0 {
+			continue
+		}
+		fmt.Fprintf(&buffer, "%s %d\n", name, quantities[name])
+	}
+	return buffer.String()
+}
+
+func exportMultibuy(w io.Writer, appraisals []Appraisal, typeDB typedb.TypeDB) error {
+	combined := &Appraisal{}
+	for _, appraisal := range appraisals {
+		combined.Original.Items = append(combined.Original.Items, appraisal.Original.Items...)
+	}
+	_, err := io.WriteString(w, AppraisalToMultibuy(combined))
+	return err
+}
+
+// AppraisalToQuickbar returns the items of the appraisal as a market quickbar export. Items that are in containers
+// (or came from quickbar folders) go into folders named after them, everything else into a folder named after
+// the appraisal.
+func AppraisalToQuickbar(appraisal *Appraisal) string {
+	topFolder := "Appraisal"
+	if appraisal.ID != "" {
+		topFolder = "Appraisal " + appraisal.ID
+	}
+
+	var paths []string
+	itemsByPath := make(map[string][]AppraisalItem)
+	for _, item := range appraisal.Original.Items {
+		if _, ok := itemsByPath[item.Extra.Path]; !ok {
+			paths = append(paths, item.Extra.Path)
+		}
+		itemsByPath[item.Extra.Path] = append(itemsByPath[item.Extra.Path], item)
+	}
+	sort.Strings(paths)
+
+	var buffer bytes.Buffer
+	var open []string
+	for _, path := range paths {
+		folders := []string{topFolder}
+		if path != "" {
+			folders = strings.Split(path, parsers.ContainerPathSeparator)
+		}
+
+		same := 0
+		for same < len(open) && same < len(folders) && open[same] == folders[same] {
+			same++
+		}
+		for depth := same; depth < len(folders); depth++ {
+			fmt.Fprintf(&buffer, "%s %s\n", strings.Repeat("+", depth+1), folders[depth])
+		}
+		open = folders
+
+		for _, item := range itemsByPath[path] {
+			fmt.Fprintf(&buffer, "- %s [%d]\n", item.DisplayName(), item.Quantity)
+		}
+	}
+	return buffer.String()
+}
+
+func exportQuickbar(w io.Writer, appraisals []Appraisal, typeDB typedb.TypeDB) error {
+	for i := range appraisals {
+		_, err := io.WriteString(w, AppraisalToQuickbar(&appraisals[i]))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,80 @@
+package evepraisal
+
+import (
+	"strings"
+
+	"github.com/evepraisal/go-evepraisal/parsers"
+	"github.com/evepraisal/go-evepraisal/typedb"
+)
+
+type fakeTypeDB struct {
+	version string
+	byName  map[string]typedb.EveType
+	byID    map[int64]typedb.EveType
+}
+
+func newFakeTypeDB(types ...typedb.EveType) *fakeTypeDB {
+	db := &fakeTypeDB{version: "fake", byName: make(map[string]typedb.EveType), byID: make(map[int64]typedb.EveType)}
+	for _, t := range types {
+		db.PutType(t)
+	}
+	return db
+}
+
+func (db *fakeTypeDB) GetType(typeName string) (typedb.EveType, bool) {
+	t, ok := db.byName[strings.ToLower(typeName)]
+	return t, ok
+}
+
+func (db *fakeTypeDB) HasType(typeName string) bool {
+	_, ok := db.GetType(typeName)
+	return ok
+}
+
+func (db *fakeTypeDB) GetTypeByID(typeID int64) (typedb.EveType, bool) {
+	t, ok := db.byID[typeID]
+	return t, ok
+}
+
+// PutType stores the type. Every type in the static dump has a portion size, so it defaults to 1.
+func (db *fakeTypeDB) PutType(t typedb.EveType) error {
+	if t.PortionSize == 0 {
+		t.PortionSize = 1
+	}
+	db.byName[strings.ToLower(t.Name)] = t
+	db.byID[t.ID] = t
+	return nil
+}
+
+func (db *fakeTypeDB) Search(s string) []typedb.EveType { return nil }
+func (db *fakeTypeDB) Version() string                  { return db.version }
+func (db *fakeTypeDB) Delete() error                    { return nil }
+func (db *fakeTypeDB) Close() error                     { return nil }
+
+// fakePriceDB has the same prices in every market. Sell is the sell min and buy the buy max of a type.
+type fakePriceDB map[int64]struct{ sell, buy float64 }
+
+func (db fakePriceDB) GetPrice(market string, typeID int64) (Prices, bool) {
+	p, ok := db[typeID]
+	if !ok {
+		return Prices{}, false
+	}
+	var prices Prices
+	prices.Sell.Min = p.sell
+	prices.Buy.Max = p.buy
+	prices.All.Volume = 1000
+	return prices, true
+}
+
+func (db fakePriceDB) UpdatePrice(market string, typeID int64, prices Prices) error { return nil }
+func (db fakePriceDB) Close() error                                                 { return nil }
+
+// newTestApp returns an app with all registered parsers that knows the types and prices
+func newTestApp(prices fakePriceDB, types ...typedb.EveType) *App {
+	typeDB := newFakeTypeDB(types...)
+	stages, err := parsers.NewParserStages(parsers.ParserDeps{TypeDB: typeDB}, parsers.ParserConfig{})
+	if err != nil {
+		panic(err)
+	}
+	return &App{TypeDB: typeDB, PriceDB: prices, Parser: NewContextMultiParser(typeDB, stages)}
+}
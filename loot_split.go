@@ -0,0 +1,172 @@
+package evepraisal
+
+import (
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/evepraisal/go-evepraisal/parsers"
+)
+
+// LootParticipant is a pilot that gets a part of the loot. Share is the participation of the pilot, like the
+// percentage of the fleet the pilot was there for. Shares only matter relative to each other.
+type LootParticipant struct {
+	Name  string  `json:"name"`
+	Share float64 `json:"share"`
+}
+
+// LootSplitRules says how loot is split. Without participants, everyone who looted something gets an equal share.
+// The corp tax is taken first, then the reserve for the FC, and whatever is left is split by share.
+type LootSplitRules struct {
+	Participants []LootParticipant `json:"participants,omitempty"`
+	TaxPercent   float64           `json:"tax_percent,omitempty"`
+	Reserve      float64           `json:"reserve,omitempty"`
+	FC           string            `json:"fc,omitempty"`
+}
+
+// LootSplit is what each pilot is owed. Values are estimated sell values, the buyback values are what the loot
+// would bring in if it is sold through the buyback program instead.
+type LootSplit struct {
+	Rules        LootSplitRules   `json:"rules"`
+	Total        float64          `json:"total"`
+	Tax          float64          `json:"tax"`
+	Reserve      float64          `json:"reserve"`
+	BuybackTotal float64          `json:"buyback_total"`
+	BuybackTax   float64          `json:"buyback_tax"`
+	Pilots       []LootSplitPilot `json:"pilots"`
+}
+
+// LootSplitPilot is the part of the loot of a single pilot. Looted is the value of what the pilot picked up and
+// Owed is what the pilot should end up with, so a positive Balance has to be paid to the pilot and a negative
+// Balance has to be handed over by the pilot.
+type LootSplitPilot struct {
+	Name        string  `json:"name"`
+	Share       float64 `json:"share"`
+	Looted      float64 `json:"looted"`
+	Owed        float64 `json:"owed"`
+	Balance     float64 `json:"balance"`
+	BuybackOwed float64 `json:"buyback_owed"`
+}
+
+var reParticipant = regexp.MustCompile(strings.Join([]string{
+	`^\s*([^\t]+?)`, // name
+	`(?:\t+\s*([\d,'\.]+)\s*%?|\s+([\d,'\.]+)\s*%)?\s*$`, // share
+}, ""))
+
+// ParseParticipationList reads a participation list with one pilot per line. A line can end with the share of
+// the pilot after a tab or as a percentage, like "Some Pilot 50%". Pilots without a share were there for the
+// whole fleet, which is 100.
+func ParseParticipationList(s string) []LootParticipant {
+	var participants []LootParticipant
+	for _, line := range strings.Split(s, "\n") {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+
+		match := reParticipant.FindStringSubmatch(strings.TrimRight(line, "\r"))
+		if match == nil {
+			continue
+		}
+
+		share := 100.0
+		if match[2] != "" {
+			share = parsers.ToDecimal(match[2])
+		} else if match[3] != "" {
+			share = parsers.ToDecimal(match[3])
+		}
+		participants = append(participants, LootParticipant{Name: match[1], Share: share})
+	}
+	return participants
+}
+
+// HasPlayers returns true if the items of the appraisal were picked up by pilots, like in a loot history
+func (appraisal *Appraisal) HasPlayers() bool {
+	for _, group := range appraisal.Groups {
+		if group.Player != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// SplitLoot splits the items of the appraisal between the pilots using the rules. What each pilot looted comes from
+// the player groups. The tax is kept between 0 and 100 percent and a negative reserve counts as no reserve.
+func (appraisal *Appraisal) SplitLoot(rules LootSplitRules) *LootSplit {
+	rules.TaxPercent = math.Max(0, math.Min(100, rules.TaxPercent))
+	rules.Reserve = math.Max(0, rules.Reserve)
+	split := &LootSplit{Rules: rules}
+
+	pilots := make(map[string]*LootSplitPilot)
+	var names []string
+	pilot := func(name string) *LootSplitPilot {
+		key := strings.ToUpper(name)
+		if _, ok := pilots[key]; !ok {
+			pilots[key] = &LootSplitPilot{Name: name}
+			names = append(names, key)
+		}
+		return pilots[key]
+	}
+
+	for _, item := range appraisal.Original.Items {
+		split.Total += item.SellTotal()
+	}
+	for _, group := range appraisal.Groups {
+		if group.Player != "" {
+			pilot(group.Player).Looted += group.Totals.Sell
+		}
+	}
+	split.BuybackTotal = appraisal.BuybackOffer()
+
+	participants := rules.Participants
+	if len(participants) == 0 {
+		for _, name := range names {
+			participants = append(participants, LootParticipant{Name: pilots[name].Name, Share: 1})
+		}
+	}
+
+	var totalShares float64
+	for _, participant := range participants {
+		if participant.Share > 0 {
+			pilot(participant.Name).Share += participant.Share
+			totalShares += participant.Share
+		}
+	}
+
+	split.Tax = split.Total * rules.TaxPercent / 100
+	split.BuybackTax = split.BuybackTotal * rules.TaxPercent / 100
+	remaining := split.Total - split.Tax
+	buybackRemaining := split.BuybackTotal - split.BuybackTax
+
+	if rules.Reserve > 0 && rules.FC != "" {
+		split.Reserve = rules.Reserve
+		if split.Reserve > remaining {
+			split.Reserve = remaining
+		}
+		buybackReserve := rules.Reserve
+		if buybackReserve > buybackRemaining {
+			buybackReserve = buybackRemaining
+		}
+		fc := pilot(rules.FC)
+		fc.Owed += split.Reserve
+		fc.BuybackOwed += buybackReserve
+		remaining -= split.Reserve
+		buybackRemaining -= buybackReserve
+	}
+
+	for _, name := range names {
+		p := pilots[name]
+		if totalShares > 0 {
+			p.Share = p.Share / totalShares
+		}
+		p.Owed += remaining * p.Share
+		p.BuybackOwed += buybackRemaining * p.Share
+		p.Balance = p.Owed - p.Looted
+		split.Pilots = append(split.Pilots, *p)
+	}
+
+	sort.Slice(split.Pilots, func(i, j int) bool {
+		return strings.ToUpper(split.Pilots[i].Name) < strings.ToUpper(split.Pilots[j].Name)
+	})
+	return split
+}
@@ -0,0 +1,138 @@
+package evepraisal
+
+import (
+	"testing"
+
+	"github.com/evepraisal/go-evepraisal/typedb"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseParticipationList(t *testing.T) {
+	assert.Equal(t, []LootParticipant{
+		{Name: "Alice", Share: 100},
+		{Name: "Bob", Share: 50},
+		{Name: "Carol", Share: 25},
+		{Name: "Dave", Share: 75},
+		{Name: "Some Pilot 50", Share: 100},
+		{Name: "Eve", Share: 1.5},
+	}, ParseParticipationList("Alice\nBob\t50\n  Carol 25%\n\nDave\t75%\r\nSome Pilot 50\nEve\t1,5"))
+
+	assert.Nil(t, ParseParticipationList(" \n\n"))
+}
+
+func TestSplitLoot(rt *testing.T) {
+	appraisal := &Appraisal{}
+	appraisal.Original.Items = []AppraisalItem{{Name: "Tritanium", Quantity: 12}}
+	appraisal.Original.Items[0].Prices.Sell.Min = 100
+	appraisal.Groups = []ItemGroup{
+		{Name: "Alice", Player: "Alice", Totals: Totals{Sell: 1000}},
+		{Name: "Bob", Player: "Bob", Totals: Totals{Sell: 200}},
+		{Name: "Cargo", Totals: Totals{Sell: 5000}},
+	}
+	appraisal.Buyback.Totals.Buy = 800
+
+	cases := []struct {
+		description string
+		rules       LootSplitRules
+		expected    *LootSplit
+	}{
+		{
+			"equal shares for everyone who looted",
+			LootSplitRules{},
+			&LootSplit{
+				Total: 1200, BuybackTotal: 800,
+				Pilots: []LootSplitPilot{
+					{Name: "Alice", Share: 0.5, Looted: 1000, Owed: 600, Balance: -400, BuybackOwed: 400},
+					{Name: "Bob", Share: 0.5, Looted: 200, Owed: 600, Balance: 400, BuybackOwed: 400},
+				},
+			},
+		}, {
+			"participation, tax and a reserve for the FC",
+			LootSplitRules{
+				Participants: []LootParticipant{{Name: "Alice", Share: 75}, {Name: "bob", Share: 25}},
+				TaxPercent:   10,
+				Reserve:      100,
+				FC:           "Carol",
+			},
+			&LootSplit{
+				Rules: LootSplitRules{
+					Participants: []LootParticipant{{Name: "Alice", Share: 75}, {Name: "bob", Share: 25}},
+					TaxPercent:   10,
+					Reserve:      100,
+					FC:           "Carol",
+				},
+				Total: 1200, Tax: 120, Reserve: 100, BuybackTotal: 800, BuybackTax: 80,
+				Pilots: []LootSplitPilot{
+					{Name: "Alice", Share: 0.75, Looted: 1000, Owed: 735, Balance: -265, BuybackOwed: 465},
+					{Name: "Bob", Share: 0.25, Looted: 200, Owed: 245, Balance: 45, BuybackOwed: 155},
+					{Name: "Carol", Owed: 100, Balance: 100, BuybackOwed: 100},
+				},
+			},
+		}, {
+			"the tax is at most 100 percent",
+			LootSplitRules{TaxPercent: 150, FC: "Alice", Reserve: 100},
+			&LootSplit{
+				Rules: LootSplitRules{TaxPercent: 100, FC: "Alice", Reserve: 100},
+				Total: 1200, Tax: 1200, BuybackTotal: 800, BuybackTax: 800,
+				Pilots: []LootSplitPilot{
+					{Name: "Alice", Share: 0.5, Looted: 1000, Balance: -1000},
+					{Name: "Bob", Share: 0.5, Looted: 200, Balance: -200},
+				},
+			},
+		}, {
+			"a negative tax or reserve counts as none",
+			LootSplitRules{TaxPercent: -10, FC: "Alice", Reserve: -500},
+			&LootSplit{
+				Rules: LootSplitRules{FC: "Alice"},
+				Total: 1200, BuybackTotal: 800,
+				Pilots: []LootSplitPilot{
+					{Name: "Alice", Share: 0.5, Looted: 1000, Owed: 600, Balance: -400, BuybackOwed: 400},
+					{Name: "Bob", Share: 0.5, Looted: 200, Owed: 600, Balance: 400, BuybackOwed: 400},
+				},
+			},
+		},
+	}
+
+	for _, c := range cases {
+		rt.Run(c.description, func(t *testing.T) {
+			assert.Equal(t, c.expected, appraisal.SplitLoot(c.rules))
+		})
+	}
+}
+
+func TestLootHistoryGroups(t *testing.T) {
+	app := newTestApp(fakePriceDB{2488: {sell: 100, buy: 90}}, typedb.EveType{ID: 2488, Name: "Garde II", Volume: 5})
+
+	appraisal, err := app.StringToAppraisal("jita", `03:21:19 Alice has looted 5 x Garde II
+03:22:20 Bob has looted 2 x Garde II
+03:23:21 alice has looted 1 x Garde II`)
+	assert.NoError(t, err)
+	assert.True(t, appraisal.HasPlayers())
+
+	// The loot of every pilot is one item, but each pilot has a group
+	if assert.Len(t, appraisal.Original.Items, 1) {
+		assert.Equal(t, int64(8), appraisal.Original.Items[0].Quantity)
+	}
+	if assert.Len(t, appraisal.Groups, 2) {
+		assert.Equal(t, "Alice", appraisal.Groups[0].Player)
+		assert.Equal(t, 600.0, appraisal.Groups[0].Totals.Sell)
+		assert.Equal(t, "Bob", appraisal.Groups[1].Player)
+		assert.Equal(t, 200.0, appraisal.Groups[1].Totals.Sell)
+	}
+
+	split := appraisal.SplitLoot(LootSplitRules{})
+	assert.Equal(t, 800.0, split.Total)
+	if assert.Len(t, split.Pilots, 2) {
+		assert.Equal(t, 600.0, split.Pilots[0].Looted)
+		assert.Equal(t, 200.0, split.Pilots[1].Looted)
+	}
+
+	// A single pilot is still split
+	appraisal, err = app.StringToAppraisal("jita", `03:21:19 Alice has looted 5 x Garde II`)
+	assert.NoError(t, err)
+	assert.True(t, appraisal.HasPlayers())
+
+	appraisal, err = app.StringToAppraisal("jita", "Garde II 5")
+	assert.NoError(t, err)
+	assert.False(t, appraisal.HasPlayers())
+}
@@ -0,0 +1,171 @@
+package management
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/evepraisal/go-evepraisal"
+)
+
+const (
+	defaultImportBatchSize = 500
+	maxImportLineSize      = 16 * 1024 * 1024
+)
+
+// ImportError describes a single record of an import that couldn't be stored
+type ImportError struct {
+	Line  int    `json:"line"`
+	ID    string `json:"id,omitempty"`
+	Error string `json:"error"`
+}
+
+// ImportReport is returned from POST /import
+type ImportReport struct {
+	Imported   int           `json:"imported"`
+	Duplicates int           `json:"duplicates"`
+	Failed     int           `json:"failed"`
+	Errors     []ImportError `json:"errors"`
+}
+
+// HandleExport streams appraisals as JSON lines. It accepts `since` (unix timestamp or RFC3339) and `kind` filters.
+func (ctx *Context) HandleExport(w http.ResponseWriter, r *http.Request) {
+	since, err := parseSince(r.FormValue("since"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	flusher, canFlush := w.(http.Flusher)
+	encoder := json.NewEncoder(w)
+	count := 0
+	err = ctx.App.AppraisalDB.ForEachAppraisal(since, r.FormValue("kind"), func(appraisal *evepraisal.Appraisal) error {
+		err := encoder.Encode(appraisal)
+		if err != nil {
+			return err
+		}
+
+		count++
+		if canFlush && count%100 == 0 {
+			flusher.Flush()
+		}
+		return nil
+	})
+
+	// The response has already started at this point, so all we can do is log the problem
+	if err != nil {
+		log.Printf("ERROR: export stopped after %d appraisals: %s", count, err)
+		return
+	}
+	log.Printf("Exported %d appraisals", count)
+}
+
+// HandleImport reads appraisals as JSON lines and stores them in batches. Each batch is written in a single
+// transaction. Appraisals with an ID that already exists are skipped.
+func (ctx *Context) HandleImport(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+
+	batchSize := defaultImportBatchSize
+	if r.FormValue("batch") != "" {
+		var err error
+		batchSize, err = strconv.Atoi(r.FormValue("batch"))
+		if err != nil || batchSize < 1 {
+			http.Error(w, "invalid batch size", http.StatusBadRequest)
+			return
+		}
+	}
+
+	report := ImportReport{Errors: make([]ImportError, 0)}
+	batch := make([]*evepraisal.Appraisal, 0, batchSize)
+	batchLines := make([]int, 0, batchSize)
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+
+		errs, err := ctx.App.AppraisalDB.PutAppraisals(batch)
+		if err != nil {
+			return err
+		}
+
+		for i, err := range errs {
+			switch err {
+			case nil:
+				report.Imported++
+			case evepraisal.AppraisalExists:
+				report.Duplicates++
+			default:
+				report.Failed++
+				report.Errors = append(report.Errors, ImportError{Line: batchLines[i], ID: batch[i].ID, Error: err.Error()})
+			}
+		}
+
+		batch = batch[:0]
+		batchLines = batchLines[:0]
+		return nil
+	}
+
+	scanner := bufio.NewScanner(r.Body)
+	scanner.Buffer(make([]byte, 64*1024), maxImportLineSize)
+	line := 0
+	for scanner.Scan() {
+		line++
+		if strings.TrimSpace(scanner.Text()) == "" {
+			continue
+		}
+
+		appraisal := &evepraisal.Appraisal{}
+		err := json.Unmarshal(scanner.Bytes(), appraisal)
+		if err != nil {
+			report.Failed++
+			report.Errors = append(report.Errors, ImportError{Line: line, Error: err.Error()})
+			continue
+		}
+
+		batch = append(batch, appraisal)
+		batchLines = append(batchLines, line)
+		if len(batch) >= batchSize {
+			err = flush()
+			if err != nil {
+				http.Error(w, fmt.Sprintf("import failed at line %d: %s", line, err), http.StatusInternalServerError)
+				return
+			}
+		}
+	}
+
+	err := scanner.Err()
+	if err == nil {
+		err = flush()
+	}
+	if err != nil {
+		http.Error(w, fmt.Sprintf("import failed at line %d: %s", line, err), http.StatusInternalServerError)
+		return
+	}
+
+	log.Printf("Imported %d appraisals (%d duplicates, %d failed)", report.Imported, report.Duplicates, report.Failed)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(report)
+}
+
+func parseSince(s string) (time.Time, error) {
+	if s == "" {
+		return time.Unix(0, 0), nil
+	}
+
+	timestamp, err := strconv.ParseInt(s, 10, 64)
+	if err == nil {
+		return time.Unix(timestamp, 0), nil
+	}
+
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return t, fmt.Errorf("invalid since value %q: use a unix timestamp or RFC3339", s)
+	}
+	return t, nil
+}
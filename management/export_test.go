@@ -0,0 +1,103 @@
+package management
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/evepraisal/go-evepraisal"
+	"github.com/evepraisal/go-evepraisal/bolt"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestContext(t *testing.T) (*Context, func()) {
+	dir, err := ioutil.TempDir("", "management")
+	assert.NoError(t, err)
+	db, err := bolt.NewAppraisalDB(filepath.Join(dir, "appraisals"))
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	return &Context{App: &evepraisal.App{AppraisalDB: db}}, func() {
+		db.Close()
+		os.RemoveAll(dir)
+	}
+}
+
+func importLines(t *testing.T, ctx *Context, body string) ImportReport {
+	w := httptest.NewRecorder()
+	ctx.HandleImport(w, httptest.NewRequest("POST", "/import?batch=2", strings.NewReader(body)))
+	assert.Equal(t, 200, w.Code, w.Body.String())
+
+	var report ImportReport
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&report))
+	return report
+}
+
+func TestExportImport(t *testing.T) {
+	src, cleanupSrc := newTestContext(t)
+	defer cleanupSrc()
+	dst, cleanupDst := newTestContext(t)
+	defer cleanupDst()
+
+	for _, appraisal := range []*evepraisal.Appraisal{
+		{ID: "abc1", Kind: "listing", Created: 1500000000, Raw: "Tritanium 5"},
+		{ID: "abc2", Kind: "eft", Created: 1600000000, Raw: "[Rifter, Rifter]"},
+		{ID: "abc3", Kind: "listing", Created: 1700000000, Raw: "Pyerite 10"},
+	} {
+		assert.NoError(t, src.App.AppraisalDB.PutNewAppraisal(appraisal))
+	}
+
+	w := httptest.NewRecorder()
+	src.HandleExport(w, httptest.NewRequest("GET", "/export?since=1550000000&kind=listing", nil))
+	assert.Equal(t, 200, w.Code)
+	exported := w.Body.String()
+	assert.Equal(t, 1, strings.Count(exported, "\n"))
+
+	w = httptest.NewRecorder()
+	src.HandleExport(w, httptest.NewRequest("GET", "/export", nil))
+	exported = w.Body.String()
+	assert.Equal(t, 3, strings.Count(exported, "\n"))
+
+	report := importLines(t, dst, exported)
+	assert.Equal(t, ImportReport{Imported: 3, Errors: []ImportError{}}, report)
+	for _, id := range []string{"abc1", "abc2", "abc3"} {
+		original, err := src.App.AppraisalDB.GetAppraisal(id)
+		assert.NoError(t, err)
+		imported, err := dst.App.AppraisalDB.GetAppraisal(id)
+		assert.NoError(t, err)
+		assert.Equal(t, original, imported)
+	}
+
+	// Importing again only finds duplicates
+	report = importLines(t, dst, exported)
+	assert.Equal(t, ImportReport{Duplicates: 3, Errors: []ImportError{}}, report)
+}
+
+func TestImportBadRecords(t *testing.T) {
+	ctx, cleanup := newTestContext(t)
+	defer cleanup()
+
+	var body bytes.Buffer
+	body.WriteString(`{"id": "good1", "kind": "listing"}` + "\n")
+	body.WriteString(`{"id": ` + "\n")
+	body.WriteString("\n")
+	body.WriteString(`{"id": "not valid", "kind": "listing"}` + "\n")
+	body.WriteString(`{"id": "good1", "kind": "listing"}` + "\n")
+	body.WriteString(`{"id": "good2", "kind": "listing"}` + "\n")
+
+	report := importLines(t, ctx, body.String())
+	assert.Equal(t, 2, report.Imported)
+	assert.Equal(t, 1, report.Duplicates)
+	assert.Equal(t, 2, report.Failed)
+	assert.Len(t, report.Errors, 2)
+	assert.Equal(t, 2, report.Errors[0].Line)
+	assert.Equal(t, ImportError{Line: 4, ID: "not valid", Error: evepraisal.InvalidAppraisalID.Error()}, report.Errors[1])
+
+	_, err := ctx.App.AppraisalDB.GetAppraisal("good2")
+	assert.NoError(t, err)
+}
@@ -3,17 +3,18 @@ package management
 import (
 	"encoding/json"
 	"expvar"
+	"io"
 	"net/http"
 	"strconv"
 
-	"github.com/boltdb/bolt"
 	"github.com/evepraisal/go-evepraisal"
-	boltdb "github.com/evepraisal/go-evepraisal/bolt"
+	"github.com/evepraisal/go-evepraisal/backup"
 	"github.com/husobee/vestigo"
 )
 
 type Context struct {
-	App *evepraisal.App
+	App     *evepraisal.App
+	Backups *backup.Manager
 }
 
 func (ctx *Context) HandleRestore(w http.ResponseWriter, r *http.Request) {
@@ -32,29 +33,54 @@ func (ctx *Context) HandleRestore(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
-func HTTPHandler(app *evepraisal.App) http.Handler {
+// HandleListBackups returns all snapshots in the backup directory
+func (ctx *Context) HandleListBackups(w http.ResponseWriter, r *http.Request) {
+	backups, err := ctx.Backups.List()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(backups)
+}
+
+// HandleCreateBackups snapshots all databases right away
+func (ctx *Context) HandleCreateBackups(w http.ResponseWriter, r *http.Request) {
+	backups, err := ctx.Backups.BackupAll()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(backups)
+}
+
+func HTTPHandler(app *evepraisal.App, backups *backup.Manager) http.Handler {
 	BackupHandleFunc := func(w http.ResponseWriter, req *http.Request) {
-		db, ok := app.AppraisalDB.(*boltdb.AppraisalDB)
+		db, ok := app.AppraisalDB.(backup.SizedSnapshotter)
 		if !ok {
 			http.Error(w, "backup not supported for this database", http.StatusInternalServerError)
 			return
 		}
-		err := db.DB.View(func(tx *bolt.Tx) error {
+		_, err := db.SizedSnapshot(func(size int64) io.Writer {
 			w.Header().Set("Content-Type", "application/octet-stream")
 			w.Header().Set("Content-Disposition", `attachment; filename="appraisals"`)
-			w.Header().Set("Content-Length", strconv.Itoa(int(tx.Size())))
-			_, err := tx.WriteTo(w)
-			return err
+			w.Header().Set("Content-Length", strconv.FormatInt(size, 10))
+			return w
 		})
 		if err != nil {
 			http.Error(w, err.Error(), http.StatusInternalServerError)
 			return
 		}
 	}
-	ctx := Context{App: app}
+	ctx := Context{App: app, Backups: backups}
 	router := vestigo.NewRouter()
 	router.Get("/backup/appraisals", BackupHandleFunc)
 	router.Post("/restore", ctx.HandleRestore)
+	router.Get("/export", ctx.HandleExport)
+	router.Post("/import", ctx.HandleImport)
+	router.Get("/backups", ctx.HandleListBackups)
+	router.Post("/backups", ctx.HandleCreateBackups)
 
 	router.Handle("/expvar", expvar.Handler())
 	return router
@@ -0,0 +1,24 @@
+package management
+
+import (
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/evepraisal/go-evepraisal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBackupAppraisals(t *testing.T) {
+	ctx, cleanup := newTestContext(t)
+	defer cleanup()
+
+	assert.NoError(t, ctx.App.AppraisalDB.PutNewAppraisal(&evepraisal.Appraisal{Kind: "listing", Raw: "Tritanium 5"}))
+
+	w := httptest.NewRecorder()
+	HTTPHandler(ctx.App, nil).ServeHTTP(w, httptest.NewRequest("GET", "/backup/appraisals", nil))
+	assert.Equal(t, 200, w.Code)
+	assert.Equal(t, "application/octet-stream", w.Header().Get("Content-Type"))
+	assert.Equal(t, strconv.Itoa(w.Body.Len()), w.Header().Get("Content-Length"))
+	assert.True(t, w.Body.Len() > 0)
+}
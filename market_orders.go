@@ -0,0 +1,100 @@
+package evepraisal
+
+import (
+	"strings"
+
+	"github.com/evepraisal/go-evepraisal/parsers"
+	"github.com/spf13/viper"
+)
+
+// IsOrder returns true if the item came from a market order list
+func (i AppraisalItem) IsOrder() bool {
+	return i.Extra.OrderType != ""
+}
+
+// OrderTotal is the ISK tied up in the order: escrow for buy orders and the asking total for sell orders
+func (i AppraisalItem) OrderTotal() float64 {
+	return float64(i.Quantity) * i.Extra.OrderPrice
+}
+
+// MarketPriceForOrder is the price that the order is competing with. Sell orders compete with the
+// lowest sell order and buy orders compete with the highest buy order.
+func (i AppraisalItem) MarketPriceForOrder() float64 {
+	if i.Extra.OrderType == "buy" {
+		return i.Prices.Buy.Max
+	}
+	return i.Prices.Sell.Min
+}
+
+// OrderPriceDifference is how far the order price is from the market price, in percent
+func (i AppraisalItem) OrderPriceDifference() float64 {
+	marketPrice := i.MarketPriceForOrder()
+	if marketPrice == 0 {
+		return 0
+	}
+	return (i.Extra.OrderPrice - marketPrice) / marketPrice * 100
+}
+
+// IsUnderpriced is true for sell orders that are asking noticeably less than the market and for buy
+// orders that are paying noticeably more than the market
+func (i AppraisalItem) IsUnderpriced() bool {
+	if !i.IsOrder() || i.MarketPriceForOrder() == 0 {
+		return false
+	}
+
+	tolerance := viper.GetFloat64("market-order-tolerance")
+	if i.Extra.OrderType == "buy" {
+		return i.OrderPriceDifference() > tolerance
+	}
+	return i.OrderPriceDifference() < -tolerance
+}
+
+// IsOverpriced is true for sell orders that are asking noticeably more than the market and for buy
+// orders that have been outbid by a noticeable margin. Neither of these are likely to fill.
+func (i AppraisalItem) IsOverpriced() bool {
+	if !i.IsOrder() || i.MarketPriceForOrder() == 0 {
+		return false
+	}
+
+	tolerance := viper.GetFloat64("market-order-tolerance")
+	if i.Extra.OrderType == "buy" {
+		return i.OrderPriceDifference() < -tolerance
+	}
+	return i.OrderPriceDifference() > tolerance
+}
+
+// OrderTotals sums up the order prices of all market orders. Buy is the ISK held in escrow by buy orders and Sell
+// is what the outstanding sell orders would bring in at their asking price.
+func (appraisal *Appraisal) OrderTotals() Totals {
+	var totals Totals
+	for _, item := range appraisal.Orders {
+		switch item.Extra.OrderType {
+		case "buy":
+			totals.Buy += item.OrderTotal()
+		case "sell":
+			totals.Sell += item.OrderTotal()
+		}
+	}
+	return totals
+}
+
+// marketOrderItems returns an item for every market order in the parser result. The appraisal items combine all
+// orders for a type, so these keep the type and price of each order.
+func marketOrderItems(result parsers.ParserResult) []AppraisalItem {
+	var items []AppraisalItem
+	switch r := result.(type) {
+	case *parsers.MultiParserResult:
+		for _, subResult := range r.Results {
+			items = append(items, marketOrderItems(subResult)...)
+		}
+	case *parsers.MarketOrders:
+		for _, order := range r.Items {
+			item := AppraisalItem{Name: strings.Trim(order.Name, " \t"), Quantity: order.Quantity}
+			item.Extra.OrderType = order.OrderType
+			item.Extra.OrderPrice = order.Price
+			item.Extra.Location = order.Station
+			items = append(items, item)
+		}
+	}
+	return items
+}
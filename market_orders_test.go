@@ -0,0 +1,86 @@
+package evepraisal
+
+import (
+	"testing"
+
+	"github.com/evepraisal/go-evepraisal/typedb"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+func orderItem(orderType string, orderPrice float64) AppraisalItem {
+	item := AppraisalItem{Name: "Tritanium", Quantity: 100}
+	item.Prices.Sell.Min = 100
+	item.Prices.Buy.Max = 90
+	item.Extra.OrderType = orderType
+	item.Extra.OrderPrice = orderPrice
+	return item
+}
+
+func TestOrderPricing(rt *testing.T) {
+	defer viper.Set("market-order-tolerance", viper.Get("market-order-tolerance"))
+	viper.Set("market-order-tolerance", 5.0)
+
+	cases := []struct {
+		description string
+		item        AppraisalItem
+		underpriced bool
+		overpriced  bool
+	}{
+		{"sell order at the market price", orderItem("sell", 100), false, false},
+		{"sell order just within the tolerance below", orderItem("sell", 95.5), false, false},
+		{"sell order below the tolerance", orderItem("sell", 94), true, false},
+		{"sell order just within the tolerance above", orderItem("sell", 104.5), false, false},
+		{"sell order above the tolerance", orderItem("sell", 106), false, true},
+		{"buy order at the market price", orderItem("buy", 90), false, false},
+		{"buy order just within the tolerance above", orderItem("buy", 94), false, false},
+		{"buy order above the tolerance", orderItem("buy", 95), true, false},
+		{"buy order just within the tolerance below", orderItem("buy", 86), false, false},
+		{"buy order below the tolerance", orderItem("buy", 85), false, true},
+		{"not an order", AppraisalItem{Name: "Tritanium"}, false, false},
+		{"no market price", func() AppraisalItem { i := orderItem("sell", 50); i.Prices.Sell.Min = 0; return i }(), false, false},
+	}
+
+	for _, c := range cases {
+		rt.Run(c.description, func(t *testing.T) {
+			assert.Equal(t, c.underpriced, c.item.IsUnderpriced(), "underpriced")
+			assert.Equal(t, c.overpriced, c.item.IsOverpriced(), "overpriced")
+		})
+	}
+}
+
+func TestOrderTotals(t *testing.T) {
+	appraisal := &Appraisal{}
+	appraisal.Original.Items = []AppraisalItem{{Name: "Tritanium", Quantity: 200}, {Name: "Pyerite", Quantity: 5}}
+	appraisal.Orders = []AppraisalItem{orderItem("buy", 90), orderItem("sell", 110)}
+	assert.Equal(t, Totals{Buy: 9000, Sell: 11000}, appraisal.OrderTotals())
+}
+
+func TestMarketOrderAppraisal(t *testing.T) {
+	app := newTestApp(fakePriceDB{34: {sell: 6, buy: 5}}, typedb.EveType{ID: 34, Name: "Tritanium", Volume: 0.01})
+
+	appraisal, err := app.StringToAppraisal("jita", `Tritanium	1,500/2,000	5.50 ISK	Jita IV - Moon 4 - Caldari Navy Assembly Plant	Region	89d 23h	Buy
+Tritanium	100/100	7.00 ISK	Jita IV - Moon 4 - Caldari Navy Assembly Plant	Station	3d 2h	Sell
+Tritanium	50/100	6.00 ISK	Amarr VIII (Oris) - Emperor Family Academy	Station	3d 2h	Sell`)
+	assert.NoError(t, err)
+
+	// The orders are one item, but every order is kept on its own
+	if assert.Len(t, appraisal.Original.Items, 1) {
+		assert.Equal(t, int64(1650), appraisal.Original.Items[0].Quantity)
+		assert.False(t, appraisal.Original.Items[0].IsOrder())
+	}
+	if assert.Len(t, appraisal.Orders, 3) {
+		locations := make(map[string]int)
+		for _, order := range appraisal.Orders {
+			assert.Equal(t, int64(34), order.TypeID)
+			assert.Equal(t, 6.0, order.Prices.Sell.Min)
+			locations[order.Extra.OrderType+" "+order.Extra.Location]++
+		}
+		assert.Equal(t, map[string]int{
+			"buy Jita IV - Moon 4 - Caldari Navy Assembly Plant":  1,
+			"sell Jita IV - Moon 4 - Caldari Navy Assembly Plant": 1,
+			"sell Amarr VIII (Oris) - Emperor Family Academy":     1,
+		}, locations)
+	}
+	assert.Equal(t, Totals{Buy: 8250, Sell: 1000}, appraisal.OrderTotals())
+}
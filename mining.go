@@ -0,0 +1,83 @@
+package evepraisal
+
+import (
+	"strings"
+
+	"github.com/evepraisal/go-evepraisal/parsers"
+)
+
+// MiningTotals adds up mining ledgers per character and per ore. Characters are only known for moon mining
+// ledgers, the personal ledger only has ores.
+type MiningTotals struct {
+	Characters []MiningTotal `json:"characters,omitempty"`
+	Ores       []MiningTotal `json:"ores"`
+}
+
+// MiningTotal is what a character mined, or what was mined of an ore. Value is the estimated value from the
+// ledger and MarketValue is what the ore is worth at the prices of the appraisal.
+type MiningTotal struct {
+	Name        string  `json:"name"`
+	Quantity    int64   `json:"quantity"`
+	Volume      float64 `json:"volume"`
+	Value       float64 `json:"value"`
+	MarketValue float64 `json:"market_value"`
+}
+
+// newMiningTotals returns the totals of the mining ledgers in the parser result, or nil if there aren't any. The
+// items are the priced appraisal items.
+func newMiningTotals(result parsers.ParserResult, items []AppraisalItem) *MiningTotals {
+	ledgers := findMiningLedgers(result)
+	if len(ledgers) == 0 {
+		return nil
+	}
+
+	prices := make(map[string]float64)
+	for _, item := range items {
+		prices[strings.ToUpper(item.Name)] = item.SingleRepresentativePrice()
+	}
+
+	ledger := &parsers.MiningLedger{}
+	characterValues := make(map[string]float64)
+	for _, l := range ledgers {
+		ledger.Items = append(ledger.Items, l.Items...)
+		for _, item := range l.Items {
+			characterValues[item.PlayerName] += float64(item.Quantity) * prices[strings.ToUpper(item.Name)]
+		}
+	}
+
+	totals := &MiningTotals{}
+	for _, total := range ledger.TotalsByCharacter() {
+		if total.Name == "" {
+			continue
+		}
+		totals.Characters = append(totals.Characters, newMiningTotal(total, characterValues[total.Name]))
+	}
+	for _, total := range ledger.TotalsByOre() {
+		totals.Ores = append(totals.Ores, newMiningTotal(total, float64(total.Quantity)*prices[strings.ToUpper(total.Name)]))
+	}
+	return totals
+}
+
+func newMiningTotal(total parsers.MiningLedgerTotal, marketValue float64) MiningTotal {
+	return MiningTotal{
+		Name:        total.Name,
+		Quantity:    total.Quantity,
+		Volume:      total.Volume,
+		Value:       total.Value,
+		MarketValue: marketValue,
+	}
+}
+
+func findMiningLedgers(result parsers.ParserResult) []*parsers.MiningLedger {
+	switch r := result.(type) {
+	case *parsers.MiningLedger:
+		return []*parsers.MiningLedger{r}
+	case *parsers.MultiParserResult:
+		var ledgers []*parsers.MiningLedger
+		for _, subResult := range r.Results {
+			ledgers = append(ledgers, findMiningLedgers(subResult)...)
+		}
+		return ledgers
+	}
+	return nil
+}
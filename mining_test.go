@@ -0,0 +1,59 @@
+package evepraisal
+
+import (
+	"testing"
+
+	"github.com/evepraisal/go-evepraisal/typedb"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMiningTotals(rt *testing.T) {
+	app := newTestApp(
+		fakePriceDB{45492: {sell: 500, buy: 400}, 45490: {sell: 1000, buy: 900}},
+		typedb.EveType{ID: 45492, Name: "Bitumens", Volume: 10},
+		typedb.EveType{ID: 45490, Name: "Coesite", Volume: 10},
+	)
+
+	rt.Run("moon mining ledger", func(t *testing.T) {
+		appraisal, err := app.StringToAppraisal("jita", `2018.03.14	Some Miner	Bitumens	20000	200000	5000000
+2018.03.15	Some Miner	Bitumens	1000	10000	250000
+2018.03.14	Another Miner	Coesite	100	1000	300000`)
+		assert.NoError(t, err)
+		assert.Equal(t, &MiningTotals{
+			Characters: []MiningTotal{
+				{Name: "Another Miner", Quantity: 100, Volume: 1000, Value: 300000, MarketValue: 100000},
+				{Name: "Some Miner", Quantity: 21000, Volume: 210000, Value: 5250000, MarketValue: 10500000},
+			},
+			Ores: []MiningTotal{
+				{Name: "Bitumens", Quantity: 21000, Volume: 210000, Value: 5250000, MarketValue: 10500000},
+				{Name: "Coesite", Quantity: 100, Volume: 1000, Value: 300000, MarketValue: 100000},
+			},
+		}, appraisal.Mining)
+
+		// Items of the same ore are combined, with the volumes added up
+		assert.Len(t, appraisal.Original.Items, 2)
+		for _, item := range appraisal.Original.Items {
+			if item.Name == "Bitumens" {
+				assert.Equal(t, int64(21000), item.Quantity)
+				assert.Equal(t, 210000.0, item.Extra.Volume)
+			}
+		}
+	})
+
+	rt.Run("personal mining ledger", func(t *testing.T) {
+		appraisal, err := app.StringToAppraisal("jita", `2018.03.14	Bitumens	100	1,000 m3	40,000 ISK	Jita
+2018.03.14	Bitumens	100	1,000 m3	40,000 ISK	Jita`)
+		assert.NoError(t, err)
+		assert.Nil(t, appraisal.Mining.Characters)
+		assert.Equal(t, []MiningTotal{{Name: "Bitumens", Quantity: 200, Volume: 2000, Value: 80000, MarketValue: 100000}}, appraisal.Mining.Ores)
+
+		assert.Len(t, appraisal.Original.Items, 1)
+		assert.Equal(t, 2000.0, appraisal.Original.Items[0].Extra.Volume)
+	})
+
+	rt.Run("not a mining ledger", func(t *testing.T) {
+		appraisal, err := app.StringToAppraisal("jita", "Bitumens 100")
+		assert.NoError(t, err)
+		assert.Nil(t, appraisal.Mining)
+	})
+}
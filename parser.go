@@ -5,33 +5,14 @@ import (
 	"github.com/evepraisal/go-evepraisal/typedb"
 )
 
-func NewContextMultiParser(typeDB typedb.TypeDB, parserList []parsers.Parser) parsers.Parser {
-	return parsers.Parser(
-		func(input parsers.Input) (parsers.ParserResult, parsers.Input) {
-			multiParserResult := &parsers.MultiParserResult{}
-			left := input
-			for _, parser := range parserList {
-				if len(left) == 0 {
-					break
-				}
-				var result parsers.ParserResult
-				result, left = parser(left)
-				if result != nil && len(result.Lines()) > 0 {
-					foundRealType := false
-					for _, item := range parserResultToAppraisalItems(result) {
-						if typeDB.HasType(item.Name) {
-							foundRealType = true
-							break
-						}
-					}
-
-					// We don't like this result, move ahead!
-					if !foundRealType {
-						continue
-					}
-					multiParserResult.Results = append(multiParserResult.Results, result)
-				}
+func NewContextMultiParser(typeDB typedb.TypeDB, stages []parsers.ParserStage) parsers.Parser {
+	return parsers.NewStagedMultiParser(stages, func(result parsers.ParserResult) bool {
+		// We don't like results without a single real type
+		for _, item := range parserResultToAppraisalItems(result) {
+			if typeDB.HasType(item.Name) {
+				return true
 			}
-			return multiParserResult, left
-		})
+		}
+		return false
+	})
 }
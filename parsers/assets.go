@@ -31,6 +31,7 @@ type AssetItem struct {
 	MetaLevel     string
 	TechLevel     string
 	PriceEstimate float64
+	Path          string
 }
 
 var reAssetList = regexp.MustCompile(strings.Join([]string{
@@ -46,11 +47,41 @@ var reAssetList = regexp.MustCompile(strings.Join([]string{
 	`(?:\t([\d,'\.\ ]+) ISK)?$`,            // price estimate
 }, ""))
 
+var reNumber = regexp.MustCompile(`^[\d,'\.\ ]+$`)
+
+// LineConfidence is higher for lines that fill in the group and category columns. A name and a quantity alone
+// could come from anywhere and numbers where names should be are most likely a different format.
+func (r *AssetList) LineConfidence(text string) (float64, string) {
+	match := reAssetList.FindStringSubmatch(text)
+	switch {
+	case match == nil:
+		return 0.75, "names the container of the lines indented below it"
+	case reNumber.MatchString(strings.TrimSpace(match[1])):
+		return 0.2, "the name column is a number"
+	case reNumber.MatchString(match[3]) || reNumber.MatchString(match[4]):
+		return 0.3, "the group or category column is a number"
+	case match[3] == "" && match[4] == "":
+		return defaultConfidence["assets"], "only has a name and a quantity"
+	default:
+		return 0.75, "has the asset list group and category columns"
+	}
+}
+
+func init() {
+	RegisterParser("assets", 200, ParseAssets)
+}
+
 func ParseAssets(input Input) (ParserResult, Input) {
 	assetList := &AssetList{}
 	matches, rest := regexParseLines(reAssetList, input)
-	assetList.lines = regexMatchedLines(matches)
-	for _, match := range matches {
+	names := make(map[int]string, len(matches))
+	for i, match := range matches {
+		names[i] = CleanTypeName(match[1])
+	}
+	paths, headers := containerPaths(input, names)
+	assetList.lines = claimHeaders(regexMatchedLines(matches), rest, headers)
+
+	for i, match := range matches {
 		qty := ToInt(match[2])
 		if qty == 0 {
 			qty = 1
@@ -68,6 +99,7 @@ func ParseAssets(input Input) (ParserResult, Input) {
 				MetaLevel:     match[8],
 				TechLevel:     match[9],
 				PriceEstimate: ToFloat64(match[10]),
+				Path:          paths[i],
 			})
 	}
 	sort.Slice(assetList.Items, func(i, j int) bool {
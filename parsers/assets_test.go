@@ -96,5 +96,27 @@ Sleeper Data Library	1.080`,
 		},
 		Input{},
 		false,
+	}, {
+		"Nested containers",
+		`Orca	1	Industrial Command Ship	Ship
+  Giant Secure Container	1	Secure Cargo Container	Celestial
+    Antimatter Charge M	1000	Hybrid Charge	Charge
+  Hobgoblin I	5	Combat Drone	Drone
+Ammo
+  Antimatter Charge M	500	Hybrid Charge	Charge
+Tritanium	100	Mineral	Material`,
+		&AssetList{
+			Items: []AssetItem{
+				{Name: "Antimatter Charge M", Quantity: 1000, Group: "Hybrid Charge", Category: "Charge", Path: "Orca > Giant Secure Container"},
+				{Name: "Antimatter Charge M", Quantity: 500, Group: "Hybrid Charge", Category: "Charge", Path: "Ammo"},
+				{Name: "Giant Secure Container", Quantity: 1, Group: "Secure Cargo Container", Category: "Celestial", Path: "Orca"},
+				{Name: "Hobgoblin I", Quantity: 5, Group: "Combat Drone", Category: "Drone", Path: "Orca"},
+				{Name: "Orca", Quantity: 1, Group: "Industrial Command Ship", Category: "Ship"},
+				{Name: "Tritanium", Quantity: 100, Group: "Mineral", Category: "Material"},
+			},
+			lines: []int{0, 1, 2, 3, 4, 5, 6},
+		},
+		Input{},
+		false,
 	},
 }
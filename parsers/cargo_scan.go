@@ -28,6 +28,10 @@ type CargoScanItem struct {
 
 var reCargoScan = regexp.MustCompile(`^([\d,'\.]+) ([\S ]+)$`)
 
+func init() {
+	RegisterParser("cargo_scan", 210, ParseCargoScan)
+}
+
 func ParseCargoScan(input Input) (ParserResult, Input) {
 	scan := &CargoScan{}
 	matches, rest := regexParseLines(reCargoScan, input)
@@ -0,0 +1,64 @@
+package parsers
+
+import "fmt"
+
+// LineScorer is implemented by parser results that can tell how sure they are about each line they claimed.
+// The confidence goes from 0 (a guess) to 1 (the line can't mean anything else) and comes with a short reason.
+type LineScorer interface {
+	LineConfidence(text string) (float64, string)
+}
+
+// defaultConfidence is used for results that don't score their own lines. Formats with headers or many fixed
+// columns are hard to match by accident, plain "name quantity" lines can come from almost anywhere.
+var defaultConfidence = map[string]float64{
+	"killmail":      1.0,
+	"xml_fitting":   1.0,
+	"dna_fitting":   1.0,
+	"spreadsheet":   1.0,
+	"quickbar":      0.95,
+	"eft":           0.95,
+	"fitting":       0.95,
+	"loot_history":  0.9,
+	"survey_scan":   0.9,
+	"mining_ledger": 0.9,
+	"market_orders": 0.9,
+	"pi":            0.9,
+	"wallet":        0.9,
+	"view_contents": 0.85,
+	"contract":      0.8,
+	"multibuy":      0.55,
+	"listing":       0.6,
+	"industry":      0.6,
+	"assets":        0.6,
+	"cargo_scan":    0.6,
+	"dscan":         0.6,
+	"heuristic":     0.4,
+}
+
+const unknownParserConfidence = 0.5
+
+// maxConfidence is the most that the registered parsers below are ever sure about a line, the others can be
+// completely sure. It has to be kept in line with their results' LineConfidence and defaultConfidence.
+var maxConfidence = map[string]float64{
+	"contract":   0.85,
+	"multibuy":   0.8,
+	"assets":     0.75,
+	"cargo_scan": 0.6,
+	"dscan":      0.6,
+	"listing":    0.6,
+	"industry":   0.85,
+	"heuristic":  0.5,
+}
+
+// LineConfidence returns how sure the parser that yielded the result is about the given line it claimed
+func LineConfidence(result ParserResult, text string) (float64, string) {
+	if scorer, ok := result.(LineScorer); ok {
+		return scorer.LineConfidence(text)
+	}
+
+	confidence, ok := defaultConfidence[result.Name()]
+	if !ok {
+		confidence = unknownParserConfidence
+	}
+	return confidence, fmt.Sprintf("matches the %s format", result.Name())
+}
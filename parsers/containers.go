@@ -0,0 +1,74 @@
+package parsers
+
+import (
+	"sort"
+	"strings"
+)
+
+// ContainerPathSeparator separates the names of nested containers in the path of an item
+const ContainerPathSeparator = " > "
+
+type containerLevel struct {
+	indent int
+	name   string
+}
+
+// containerPaths works out which containers the items are in from the indentation of the lines. Lines are
+// inside the closest line above them that is indented less. items has the name of the item on each line that was
+// parsed; a line without an item (and without tabs) that has items indented below it is a header naming a
+// container. It returns the path of every item line that is inside a container and the header lines.
+func containerPaths(input Input, items map[int]string) (map[int]string, []int) {
+	var lines []int
+	for _, line := range input.LineNumbers() {
+		if strings.TrimSpace(input[line]) != "" {
+			lines = append(lines, line)
+		}
+	}
+
+	paths := make(map[int]string)
+	var headers []int
+	var stack []containerLevel
+	for i, line := range lines {
+		indent := indentation(input[line])
+		for len(stack) > 0 && stack[len(stack)-1].indent >= indent {
+			stack = stack[:len(stack)-1]
+		}
+
+		name, isItem := items[line]
+		if isItem && len(stack) > 0 {
+			names := make([]string, len(stack))
+			for j, level := range stack {
+				names[j] = level.name
+			}
+			paths[line] = strings.Join(names, ContainerPathSeparator)
+		}
+
+		if i+1 == len(lines) || indentation(input[lines[i+1]]) <= indent {
+			continue
+		}
+
+		if !isItem {
+			if _, nextIsItem := items[lines[i+1]]; !nextIsItem || strings.Contains(input[line], "\t") {
+				continue
+			}
+			name = strings.TrimSuffix(strings.TrimSpace(input[line]), ":")
+			headers = append(headers, line)
+		}
+		stack = append(stack, containerLevel{indent: indent, name: name})
+	}
+	return paths, headers
+}
+
+func indentation(s string) int {
+	return len(s) - len(strings.TrimLeft(s, " "))
+}
+
+// claimHeaders takes the header lines out of the rest and adds them to the lines of the result
+func claimHeaders(lines []int, rest Input, headers []int) []int {
+	for _, line := range headers {
+		delete(rest, line)
+		lines = append(lines, line)
+	}
+	sort.Ints(lines)
+	return lines
+}
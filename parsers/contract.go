@@ -47,6 +47,31 @@ var reContractShort = regexp.MustCompile(strings.Join([]string{
 
 var reBPCDetails = regexp.MustCompile(`BLUEPRINT COPY - Runs: ([\d]+) - .*`)
 
+// LineConfidence is higher for lines with all five columns of the contract window than for the short form with
+// only a name, a quantity and a type. Either is unlikely when the name is a number.
+func (r *Contract) LineConfidence(text string) (float64, string) {
+	match := reContract.FindStringSubmatch(text)
+	if match == nil {
+		match = reContractShort.FindStringSubmatch(text)
+		if match == nil {
+			return defaultConfidence["contract"], "matches the contract format"
+		}
+	}
+
+	switch {
+	case reNumber.MatchString(strings.TrimSpace(match[1])):
+		return 0.2, "the name column is a number"
+	case len(match) == 6:
+		return 0.85, "has the type, category and details columns"
+	default:
+		return defaultConfidence["contract"], "only has a name, a quantity and a type"
+	}
+}
+
+func init() {
+	RegisterParser("contract", 160, ParseContract)
+}
+
 func ParseContract(input Input) (ParserResult, Input) {
 	contract := &Contract{}
 	matches, rest := regexParseLines(reContract, input)
@@ -32,6 +32,10 @@ var reDScan = regexp.MustCompile(strings.Join([]string{
 	`((?:([\d,'\.` + "\xc2\xa0" + `]*) (m|km|AU))|-)`, // Distance
 }, ""))
 
+func init() {
+	RegisterParser("dscan", 220, ParseDScan)
+}
+
 func ParseDScan(input Input) (ParserResult, Input) {
 	dscan := &DScan{}
 	matches, rest := regexParseLines(reDScan, input)
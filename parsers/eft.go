@@ -23,47 +23,110 @@ func (r *EFT) Lines() []int {
 	return r.lines
 }
 
+// EFTList is the result of parsing a paste with more than one EFT fitting in it
+type EFTList struct {
+	Fittings []*EFT
+	lines    []int
+}
+
+func (r *EFTList) Name() string {
+	return "eft"
+}
+
+func (r *EFTList) Lines() []int {
+	return r.lines
+}
+
 var reEFTHeader = regexp.MustCompile(`^\[([\S ]+), ?([\S ]+)\]$`)
+var reEFTOffline = regexp.MustCompile(`(?i)\s*/offline$`)
 var eftBlacklist = map[string]bool{
 	"[empty high slot]":      true,
 	"[empty low slot]":       true,
+	"[empty med slot]":       true,
+	"[empty mid slot]":       true,
 	"[empty medium slot]":    true,
 	"[empty rig slot]":       true,
 	"[empty subsystem slot]": true,
+	"[empty service slot]":   true,
+}
+
+func init() {
+	RegisterParser("eft", 60, ParseEFT)
 }
 
+// ParseEFT parses one or more EFT fittings. The paste has to start with a fitting header and every following
+// header starts a new fitting. A single fitting is returned as *EFT, more than one as *EFTList.
 func ParseEFT(input Input) (ParserResult, Input) {
-	inputLines := input.Strings()
-	if len(inputLines) == 0 {
+	lineNumbers := input.LineNumbers()
+	if len(lineNumbers) == 0 {
 		return nil, input
 	}
 
-	line0 := inputLines[0]
+	line0 := input[lineNumbers[0]]
 	if !strings.Contains(line0, "[") || !strings.Contains(line0, "]") {
 		return nil, input
 	}
 
-	headerParts := reEFTHeader.FindStringSubmatch(line0)
-	if len(headerParts) == 0 {
+	if !reEFTHeader.MatchString(line0) {
 		return nil, input
 	}
 
+	list := &EFTList{}
+	rest := make(Input)
+	var headerLine int
+	itemsInput := make(Input)
+	for i, lineNumber := range lineNumbers {
+		if i > 0 && !reEFTHeader.MatchString(input[lineNumber]) {
+			itemsInput[lineNumber] = input[lineNumber]
+			continue
+		}
+
+		if i > 0 {
+			eft, eftRest := parseEFTFitting(headerLine, input[headerLine], itemsInput)
+			list.Fittings = append(list.Fittings, eft)
+			for k, v := range eftRest {
+				rest[k] = v
+			}
+		}
+		headerLine = lineNumber
+		itemsInput = make(Input)
+	}
+	eft, eftRest := parseEFTFitting(headerLine, input[headerLine], itemsInput)
+	list.Fittings = append(list.Fittings, eft)
+	for k, v := range eftRest {
+		rest[k] = v
+	}
+
+	if len(list.Fittings) == 1 {
+		return list.Fittings[0], rest
+	}
+
+	for _, eft := range list.Fittings {
+		list.lines = append(list.lines, eft.lines...)
+	}
+	sort.Ints(list.lines)
+	return list, rest
+}
+
+func parseEFTFitting(headerLine int, header string, itemsInput Input) (*EFT, Input) {
+	headerParts := reEFTHeader.FindStringSubmatch(header)
+
 	eft := &EFT{}
-	eft.lines = []int{0}
+	eft.lines = []int{headerLine}
 	eft.Ship = headerParts[1]
 	eft.FittingName = headerParts[2]
 
-	itemsInput := StringsToInput(inputLines)
-	// remove the header line (it was done this way to maintain the correct line numbers)
-	delete(itemsInput, 0)
-
-	// remove blacklisted lines
 	for i, line := range itemsInput {
+		// remove blacklisted lines
 		_, blacklisted := eftBlacklist[strings.ToLower(line)]
 		if blacklisted {
 			eft.lines = append(eft.lines, i)
 			delete(itemsInput, i)
+			continue
 		}
+
+		// offline modules are still part of the fit
+		itemsInput[i] = reEFTOffline.ReplaceAllString(line, "")
 	}
 
 	result, rest := ParseListing(itemsInput)
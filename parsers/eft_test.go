@@ -96,5 +96,48 @@ XL Torpedo Launcher I,Guristas Mjolnir XL Torpedo`,
 		},
 		Input{1: ""},
 		true,
+	}, {
+		"Multiple fittings",
+		`[Rifter, Tackle]
+Damage Control II
+[Empty Med slot]
+200mm AutoCannon II, Republic Fleet EMP S
+
+[Merlin, Brawler]
+Small Shield Booster II /OFFLINE
+Light Neutron Blaster II, Null S
+
+Warrior II x3
+
+Nanite Repair Paste x50`,
+		&EFTList{
+			Fittings: []*EFT{
+				{
+					FittingName: "Tackle",
+					Ship:        "Rifter",
+					Items: []ListingItem{
+						{Name: "200mm AutoCannon II", Quantity: 1},
+						{Name: "Damage Control II", Quantity: 1},
+						{Name: "Republic Fleet EMP S", Quantity: 1},
+					},
+					lines: []int{0, 1, 2, 3},
+				},
+				{
+					FittingName: "Brawler",
+					Ship:        "Merlin",
+					Items: []ListingItem{
+						{Name: "Light Neutron Blaster II", Quantity: 1},
+						{Name: "Nanite Repair Paste", Quantity: 50},
+						{Name: "Null S", Quantity: 1},
+						{Name: "Small Shield Booster II", Quantity: 1},
+						{Name: "Warrior II", Quantity: 3},
+					},
+					lines: []int{5, 6, 7, 9, 11},
+				},
+			},
+			lines: []int{0, 1, 2, 3, 5, 6, 7, 9, 11},
+		},
+		Input{4: "", 8: "", 10: ""},
+		true,
 	},
 }
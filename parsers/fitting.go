@@ -7,8 +7,15 @@ import (
 )
 
 type Fitting struct {
+	Items    []ListingItem
+	Sections []FittingSection
+	lines    []int
+}
+
+// FittingSection holds the items listed under one of the section headers, like "High power"
+type FittingSection struct {
+	Name  string
 	Items []ListingItem
-	lines []int
 }
 
 func (r *Fitting) Name() string {
@@ -30,24 +37,48 @@ var fittingBlacklist = map[string]bool{
 	"Fuel":         true,
 }
 
+func init() {
+	RegisterParser("fitting", 70, ParseFitting)
+}
+
 func ParseFitting(input Input) (ParserResult, Input) {
 	fitting := &Fitting{}
 
-	// remove blacklisted lines
+	// remove blacklisted lines, remembering which section every other line is in
 	isFitting := false
-	for i, line := range input {
+	section := ""
+	sectionInputs := make(map[string]Input)
+	var sectionNames []string
+	items := make(Input)
+	for _, i := range input.LineNumbers() {
+		line := input[i]
 		_, blacklisted := fittingBlacklist[line]
 		if blacklisted {
 			isFitting = true
+			section = line
 			fitting.lines = append(fitting.lines, i)
-			delete(input, i)
+			continue
+		}
+		items[i] = line
+
+		if _, ok := sectionInputs[section]; !ok {
+			sectionInputs[section] = make(Input)
+			sectionNames = append(sectionNames, section)
 		}
+		sectionInputs[section][i] = line
 	}
 	if !isFitting {
 		return nil, input
 	}
 
-	result, rest := ParseListing(input)
+	for _, name := range sectionNames {
+		result, _ := ParseListing(sectionInputs[name])
+		if items := result.(*Listing).Items; len(items) > 0 {
+			fitting.Sections = append(fitting.Sections, FittingSection{Name: name, Items: items})
+		}
+	}
+
+	result, rest := ParseListing(items)
 	listingResult, ok := result.(*Listing)
 	if !ok {
 		log.Fatal("ParseListing returned something other than parsers.Listing")
@@ -61,3 +92,10 @@ func ParseFitting(input Input) (ParserResult, Input) {
 	sort.Ints(fitting.lines)
 	return fitting, rest
 }
+
+// ShipFitting is a single fit from a format that can hold several of them
+type ShipFitting struct {
+	Name  string
+	Ship  string
+	Items []ListingItem
+}
@@ -0,0 +1,87 @@
+package parsers
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/evepraisal/go-evepraisal/typedb"
+)
+
+type DNAFitting struct {
+	Fittings []ShipFitting
+	lines    []int
+}
+
+func (r *DNAFitting) Name() string {
+	return "dna_fitting"
+}
+
+func (r *DNAFitting) Lines() []int {
+	return r.lines
+}
+
+// Ship DNA, optionally wrapped in a chat link: <url=fitting:shipID:modID;qty:modID;qty::>Name</url>
+var reDNA = regexp.MustCompile(`^\s*(?:<url=)?(?:fitting:)?(\d+(?::\d+_?;\d+)*)::(?:>([^<]*)</url>)?\s*$`)
+
+type DNAParser struct {
+	typeDB typedb.TypeDB
+}
+
+func init() {
+	Register("dna_fitting", 40, func(deps ParserDeps) Parser {
+		if deps.TypeDB == nil {
+			return nil
+		}
+		return NewDNAParser(deps.TypeDB)
+	})
+}
+
+func NewDNAParser(typeDB typedb.TypeDB) Parser {
+	p := &DNAParser{typeDB: typeDB}
+	return p.Parse
+}
+
+// Parse resolves ship DNA strings (one fit per line) through the type database
+func (p *DNAParser) Parse(input Input) (ParserResult, Input) {
+	result := &DNAFitting{}
+	matches, rest := regexParseLines(reDNA, input)
+	for _, lineNumber := range regexMatchedLines(matches) {
+		match := matches[lineNumber]
+		parts := strings.Split(match[1], ":")
+
+		shipID, _ := strconv.ParseInt(parts[0], 10, 64)
+		ship, ok := p.typeDB.GetTypeByID(shipID)
+		if !ok {
+			rest[lineNumber] = input[lineNumber]
+			continue
+		}
+
+		fitting := ShipFitting{Name: strings.TrimSpace(match[2]), Ship: ship.Name}
+		matchgroup := make(map[ListingItem]int64)
+		for _, part := range parts[1:] {
+			idAndQuantity := strings.SplitN(part, ";", 2)
+			// a trailing underscore marks charges that are loaded or in cargo
+			typeID, _ := strconv.ParseInt(strings.TrimSuffix(idAndQuantity[0], "_"), 10, 64)
+			t, ok := p.typeDB.GetTypeByID(typeID)
+			if !ok {
+				continue
+			}
+			matchgroup[ListingItem{Name: t.Name}] += ToInt(idAndQuantity[1])
+		}
+
+		for item, quantity := range matchgroup {
+			item.Quantity = quantity
+			fitting.Items = append(fitting.Items, item)
+		}
+		sort.Slice(fitting.Items, func(i, j int) bool {
+			return fmt.Sprintf("%v", fitting.Items[i]) < fmt.Sprintf("%v", fitting.Items[j])
+		})
+
+		result.Fittings = append(result.Fittings, fitting)
+		result.lines = append(result.lines, lineNumber)
+	}
+	return result, rest
+}
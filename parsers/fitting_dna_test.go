@@ -0,0 +1,98 @@
+package parsers
+
+import (
+	"testing"
+
+	"github.com/evepraisal/go-evepraisal/typedb"
+	"github.com/stretchr/testify/assert"
+)
+
+var dnaTypes = []typedb.EveType{
+	{ID: 587, Name: "Rifter"},
+	{ID: 2873, Name: "125mm Gatling AutoCannon II"},
+	{ID: 5973, Name: "5MN Cold-Gas Enduring Microwarpdrive"},
+	{ID: 2048, Name: "Damage Control II"},
+	{ID: 21894, Name: "Republic Fleet EMP S"},
+	{ID: 603, Name: "Merlin"},
+}
+
+var DNAParserCases = []struct {
+	name   string
+	in     string
+	result ParserResult
+	left   Input
+}{
+	{
+		"Plain DNA",
+		`587:2873;3:5973;1:2048;1:21894_;400::`,
+		&DNAFitting{
+			Fittings: []ShipFitting{
+				{
+					Ship: "Rifter",
+					Items: []ListingItem{
+						{Name: "125mm Gatling AutoCannon II", Quantity: 3},
+						{Name: "5MN Cold-Gas Enduring Microwarpdrive", Quantity: 1},
+						{Name: "Damage Control II", Quantity: 1},
+						{Name: "Republic Fleet EMP S", Quantity: 400},
+					},
+				},
+			},
+			lines: []int{0},
+		},
+		Input{},
+	}, {
+		"Chat links",
+		`<url=fitting:587:2873;3:2048;1::>Frigate Roam</url>
+<url=fitting:603:2048;1::>Cheap Merlin</url>`,
+		&DNAFitting{
+			Fittings: []ShipFitting{
+				{
+					Name: "Frigate Roam",
+					Ship: "Rifter",
+					Items: []ListingItem{
+						{Name: "125mm Gatling AutoCannon II", Quantity: 3},
+						{Name: "Damage Control II", Quantity: 1},
+					},
+				},
+				{
+					Name:  "Cheap Merlin",
+					Ship:  "Merlin",
+					Items: []ListingItem{{Name: "Damage Control II", Quantity: 1}},
+				},
+			},
+			lines: []int{0, 1},
+		},
+		Input{},
+	}, {
+		"Unknown types",
+		`587:99999;1:2048;1::
+12345:2048;1::`,
+		&DNAFitting{
+			Fittings: []ShipFitting{
+				{
+					Ship:  "Rifter",
+					Items: []ListingItem{{Name: "Damage Control II", Quantity: 1}},
+				},
+			},
+			lines: []int{0},
+		},
+		Input{1: "12345:2048;1::"},
+	},
+}
+
+func TestDNAParser(rt *testing.T) {
+	for _, c := range DNAParserCases {
+		rt.Run(c.name, func(t *testing.T) {
+			db := &StaticTypeDB{
+				typeNameMap: make(map[string]typedb.EveType),
+				typeIDMap:   make(map[int64]typedb.EveType),
+			}
+			for _, t := range dnaTypes {
+				db.PutType(t)
+			}
+			result, rest := NewDNAParser(db)(StringToInput(c.in))
+			assert.Equal(t, c.result, result, "results should be the same")
+			assert.Equal(t, c.left, rest, "the rest should be the same")
+		})
+	}
+}
@@ -54,6 +54,33 @@ Helium Isotopes`,
 				{Name: "Tengu Offensive - Accelerated Ejection Bay", Quantity: 1},
 				{Name: "Tengu Propulsion - Fuel Catalyst", Quantity: 1},
 				{Name: "Warrior II", Quantity: 12}},
+			Sections: []FittingSection{
+				{Name: "High power", Items: []ListingItem{{Name: "Heavy Missile Launcher II", Quantity: 5}}},
+				{Name: "Medium power", Items: []ListingItem{
+					{Name: "Adaptive Invulnerability Field II", Quantity: 2},
+					{Name: "Domination 100MN Afterburner", Quantity: 1},
+					{Name: "Dread Guristas EM Ward Amplifier", Quantity: 1},
+					{Name: "Large Shield Extender II", Quantity: 1},
+					{Name: "Phased Muon Sensor Disruptor I", Quantity: 1}}},
+				{Name: "Low power", Items: []ListingItem{
+					{Name: "Ballistic Control System II", Quantity: 3},
+					{Name: "Damage Control II", Quantity: 1},
+					{Name: "Reactor Control Unit II", Quantity: 1}}},
+				{Name: "Rig Slot", Items: []ListingItem{
+					{Name: "Medium Ancillary Current Router I", Quantity: 1},
+					{Name: "Medium Core Defense Field Extender I", Quantity: 2}}},
+				{Name: "Sub System", Items: []ListingItem{
+					{Name: "Tengu Defensive - Supplemental Screening", Quantity: 1},
+					{Name: "Tengu Electronics - Dissolution Sequencer", Quantity: 1},
+					{Name: "Tengu Engineering - Capacitor Regeneration Matrix", Quantity: 1},
+					{Name: "Tengu Offensive - Accelerated Ejection Bay", Quantity: 1},
+					{Name: "Tengu Propulsion - Fuel Catalyst", Quantity: 1}}},
+				{Name: "Charges", Items: []ListingItem{
+					{Name: "Caldari Navy Scourge Heavy Missile", Quantity: 8718},
+					{Name: "Targeting Range Dampening Script", Quantity: 1}}},
+				{Name: "Drones", Items: []ListingItem{{Name: "Warrior II", Quantity: 12}}},
+				{Name: "Fuel", Items: []ListingItem{{Name: "Helium Isotopes", Quantity: 1}}},
+			},
 			lines: []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 18, 19, 20, 21, 22, 23, 24, 25, 26, 27, 28}},
 		Input{},
 		true,
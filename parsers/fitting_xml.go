@@ -0,0 +1,103 @@
+package parsers
+
+import (
+	"encoding/xml"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+type XMLFitting struct {
+	Fittings []ShipFitting
+	lines    []int
+}
+
+func (r *XMLFitting) Name() string {
+	return "xml_fitting"
+}
+
+func (r *XMLFitting) Lines() []int {
+	return r.lines
+}
+
+type xmlFittings struct {
+	Fittings []struct {
+		Name     string `xml:"name,attr"`
+		ShipType struct {
+			Value string `xml:"value,attr"`
+		} `xml:"shipType"`
+		Hardware []struct {
+			Type string `xml:"type,attr"`
+			Qty  string `xml:"qty,attr"`
+			Slot string `xml:"slot,attr"`
+		} `xml:"hardware"`
+	} `xml:"fitting"`
+}
+
+func init() {
+	RegisterParser("xml_fitting", 30, ParseXMLFitting)
+}
+
+// ParseXMLFitting parses fittings exported from the EVE client (or pyfa) as XML
+func ParseXMLFitting(input Input) (ParserResult, Input) {
+	lineNumbers := input.LineNumbers()
+
+	// Find where the XML document starts and ends so that anything around it is left alone
+	start, end := -1, -1
+	for i, lineNumber := range lineNumbers {
+		line := input[lineNumber]
+		if start == -1 && (strings.Contains(line, "<?xml") || strings.Contains(line, "<fittings")) {
+			start = i
+		}
+		if start != -1 && strings.Contains(line, "</fittings>") {
+			end = i
+			break
+		}
+	}
+	if start == -1 || end == -1 {
+		return nil, input
+	}
+
+	docLines := make([]string, 0, end-start+1)
+	for _, lineNumber := range lineNumbers[start : end+1] {
+		docLines = append(docLines, input[lineNumber])
+	}
+
+	var doc xmlFittings
+	err := xml.Unmarshal([]byte(strings.Join(docLines, "\n")), &doc)
+	if err != nil || len(doc.Fittings) == 0 {
+		return nil, input
+	}
+
+	result := &XMLFitting{lines: lineNumbers[start : end+1]}
+	for _, f := range doc.Fittings {
+		fitting := ShipFitting{Name: f.Name, Ship: f.ShipType.Value}
+
+		matchgroup := make(map[ListingItem]int64)
+		for _, hardware := range f.Hardware {
+			quantity := int64(1)
+			if hardware.Qty != "" {
+				quantity = ToInt(hardware.Qty)
+			}
+			matchgroup[ListingItem{Name: CleanTypeName(hardware.Type)}] += quantity
+		}
+
+		for item, quantity := range matchgroup {
+			item.Quantity = quantity
+			fitting.Items = append(fitting.Items, item)
+		}
+		sort.Slice(fitting.Items, func(i, j int) bool {
+			return fmt.Sprintf("%v", fitting.Items[i]) < fmt.Sprintf("%v", fitting.Items[j])
+		})
+		result.Fittings = append(result.Fittings, fitting)
+	}
+
+	rest := make(Input)
+	for _, lineNumber := range lineNumbers[:start] {
+		rest[lineNumber] = input[lineNumber]
+	}
+	for _, lineNumber := range lineNumbers[end+1:] {
+		rest[lineNumber] = input[lineNumber]
+	}
+	return result, rest
+}
@@ -0,0 +1,97 @@
+package parsers
+
+var xmlFittingTestCases = []Case{
+	{
+		"Single fitting",
+		`<?xml version="1.0" ?>
+<fittings>
+  <fitting name="Doctrine Kestrel">
+    <description value=""/>
+    <shipType value="Kestrel"/>
+    <hardware flag="HiSlot0" slot="hi slot 0" type="Light Missile Launcher II"/>
+    <hardware flag="HiSlot1" slot="hi slot 1" type="Light Missile Launcher II"/>
+    <hardware flag="MedSlot0" slot="med slot 0" type="1MN Afterburner II"/>
+    <hardware flag="LoSlot0" slot="low slot 0" type="Ballistic Control System II"/>
+    <hardware qty="500" slot="cargo" type="Scourge Light Missile"/>
+  </fitting>
+</fittings>`,
+		&XMLFitting{
+			Fittings: []ShipFitting{
+				{
+					Name: "Doctrine Kestrel",
+					Ship: "Kestrel",
+					Items: []ListingItem{
+						{Name: "1MN Afterburner II", Quantity: 1},
+						{Name: "Ballistic Control System II", Quantity: 1},
+						{Name: "Light Missile Launcher II", Quantity: 2},
+						{Name: "Scourge Light Missile", Quantity: 500},
+					},
+				},
+			},
+			lines: []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11},
+		},
+		Input{},
+		true,
+	}, {
+		"Multiple fittings",
+		`<?xml version="1.0" ?>
+<fittings>
+  <fitting name="Tackle">
+    <shipType value="Atron"/>
+    <hardware slot="hi slot 0" type="Small Focused Pulse Laser II"/>
+    <hardware slot="drone bay" qty="2" type="Hobgoblin II"/>
+  </fitting>
+  <fitting name="Logi">
+    <shipType value="Scalpel"/>
+    <hardware slot="med slot 0" type="Small Remote Shield Booster II"/>
+    <hardware slot="med slot 1" type="Small Remote Shield Booster II"/>
+  </fitting>
+</fittings>`,
+		&XMLFitting{
+			Fittings: []ShipFitting{
+				{
+					Name: "Tackle",
+					Ship: "Atron",
+					Items: []ListingItem{
+						{Name: "Hobgoblin II", Quantity: 2},
+						{Name: "Small Focused Pulse Laser II", Quantity: 1},
+					},
+				},
+				{
+					Name: "Logi",
+					Ship: "Scalpel",
+					Items: []ListingItem{
+						{Name: "Small Remote Shield Booster II", Quantity: 2},
+					},
+				},
+			},
+			lines: []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12},
+		},
+		Input{},
+		true,
+	}, {
+		"With text around it",
+		`Here's the fit:
+<fittings><fitting name="Rifter"><shipType value="Rifter"/><hardware slot="hi slot 0" type="200mm AutoCannon II"/></fitting></fittings>
+thanks`,
+		&XMLFitting{
+			Fittings: []ShipFitting{
+				{
+					Name:  "Rifter",
+					Ship:  "Rifter",
+					Items: []ListingItem{{Name: "200mm AutoCannon II", Quantity: 1}},
+				},
+			},
+			lines: []int{1},
+		},
+		Input{0: "Here's the fit:", 2: "thanks"},
+		false,
+	}, {
+		"Unterminated",
+		`<fittings>
+  <fitting name="Rifter">`,
+		nil,
+		Input{0: "<fittings>", 1: `  <fitting name="Rifter">`},
+		false,
+	},
+}
@@ -60,6 +60,36 @@ type HeuristicItem struct {
 	Quantity int64
 }
 
+// LineConfidence is low for every line since the heuristic parser only runs when nothing else fits. A quantity in
+// its own tab separated column makes the guess a little better, a type name found in the middle of a line without
+// any quantity makes it worse.
+func (r *HeuristicResult) LineConfidence(text string) (float64, string) {
+	parts := removeEmpty(heuristicTrimStrings(strings.Split(text, "\t"), ", _=-[]*"))
+	if len(parts) > 1 {
+		for _, part := range parts {
+			if reNumber.MatchString(part) {
+				return 0.5, "a known type name with the quantity in its own column"
+			}
+		}
+	}
+
+	for _, part := range strings.Fields(text) {
+		if reNumber.MatchString(strings.Trim(part, ",xX")) {
+			return defaultConfidence["heuristic"], "guessed a known type name and a quantity"
+		}
+	}
+	return 0.3, "found a known type name but no quantity"
+}
+
+func init() {
+	Register("heuristic", 1000, func(deps ParserDeps) Parser {
+		if deps.TypeDB == nil {
+			return nil
+		}
+		return NewHeuristicParser(deps.TypeDB)
+	})
+}
+
 func NewHeuristicParser(typeDB typedb.TypeDB) Parser {
 	p := &HeuristicParser{typeDB: typeDB}
 	return p.Parse
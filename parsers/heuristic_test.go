@@ -71,6 +71,11 @@ type StaticTypeDB struct {
 
 func (db *StaticTypeDB) PutType(t typedb.EveType) error {
 	db.typeNameMap[strings.ToLower(t.Name)] = t
+	for _, name := range t.LocalizedNames {
+		if _, ok := db.typeNameMap[strings.ToLower(name)]; !ok {
+			db.typeNameMap[strings.ToLower(name)] = t
+		}
+	}
 	db.typeIDMap[t.ID] = t
 	return nil
 }
@@ -93,6 +98,10 @@ func (db *StaticTypeDB) Search(s string) []typedb.EveType {
 	return nil
 }
 
+func (db *StaticTypeDB) Version() string {
+	return "static"
+}
+
 func (db *StaticTypeDB) Delete() error {
 	return nil
 }
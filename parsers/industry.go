@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"regexp"
 	"sort"
+	"strings"
 )
 
 type Industry struct {
@@ -19,17 +20,100 @@ func (r *Industry) Lines() []int {
 	return r.lines
 }
 
+// IndustryItem is a material or, when Runs is set, the product of an industry job. The quantity of a job
+// product is in runs since the output per run is only known from the static dump.
 type IndustryItem struct {
 	Name     string
 	Quantity int64
+	Runs     int64
 }
 
-var reIndustry = regexp.MustCompile(`^([\S ]+) \(([\d]+) Units?\)$`)
+var reIndustry = regexp.MustCompile(`^([\S ]+) \(([\d,'\.]+) ` + keywordPattern(localizedUnits) + `\)$`)
+
+// Material requirements: name, required, available, missing
+var reIndustryMaterials = regexp.MustCompile(strings.Join([]string{
+	`^([^\t]+)\t`,     // name
+	`([\d,'\.\ ]+)\t`, // required
+	`([\d,'\.\ ]+)\t`, // available
+	`([\d,'\.\ ]+)$`,  // missing
+}, ""))
+
+var reIndustryMaterialsHeader = regexp.MustCompile(`^(?i)(item|material|name|type)s?\trequired\tavailable\tmissing$`)
+
+// Jobs: any number of columns with a blueprint that is followed by the number of runs. A blueprint and a
+// number alone are also what a plain item list looks like, so job lines need an activity or status column or a
+// job list header above them.
+var reIndustryJob = regexp.MustCompile(strings.Join([]string{
+	`^((?:[^\t]*\t)*?)`,                                      // leading columns (status, activity, ...)
+	`([^\t]+) (?:Blueprint|Reaction Formula)\t`,              // blueprint
+	`([\d,'\.]+)(?: ` + keywordPattern(localizedRuns) + `)?`, // runs
+	`((?:\t[^\t]*)*)$`,                                       // trailing columns (installer, location, end date, ...)
+}, ""))
+
+// Jobs for these activities don't produce the blueprint's product
+var reIndustryNonManufacturingJob = regexp.MustCompile(`(?i)(^|\t)(copying|invention|(material|time) efficiency research|research|reverse engineering)(\t|$)`)
+
+// Columns of the jobs list that only industry jobs have
+var reIndustryJobContext = regexp.MustCompile(`(?i)(^|\t)(manufacturing|reactions?|copying|invention|(material|time) efficiency research|research|reverse engineering|in progress|ready|delivered|paused|cancelled|failed)(\t|$)`)
+
+var reIndustryJobsHeader = regexp.MustCompile(`^(?i)(?:[^\t]*\t)*(status|activity)\t(?:[^\t]*\t)*runs(?:\t[^\t]*)*$`)
+
+// LineConfidence is higher than for a plain listing since every industry format has something that only
+// industry pastes have: a unit count, the required/available/missing columns or the activity of a job.
+func (r *Industry) LineConfidence(text string) (float64, string) {
+	switch {
+	case reIndustry.MatchString(text):
+		return 0.8, "names the quantity in units"
+	case reIndustryMaterialsHeader.MatchString(text), reIndustryJobsHeader.MatchString(text):
+		return 0.85, "is the header of an industry window"
+	case reIndustryMaterials.MatchString(text):
+		return 0.85, "has the required, available and missing columns"
+	case reIndustryJobContext.MatchString(text):
+		return 0.85, "is a job with its activity or status"
+	default:
+		return 0.7, "is a job below a job list header"
+	}
+}
+
+func init() {
+	RegisterParser("industry", 240, ParseIndustry)
+}
 
 func ParseIndustry(input Input) (ParserResult, Input) {
 	industry := &Industry{}
 	matches, rest := regexParseLines(reIndustry, input)
+	materialMatches, rest := regexParseLines(reIndustryMaterials, rest)
+	jobMatches, rest := regexParseLines(reIndustryJob, rest)
+
+	// Without a header, only lines with an activity or status column are jobs
+	jobsHeaderMatches, rest := regexParseLines(reIndustryJobsHeader, rest)
+	if len(jobsHeaderMatches) == 0 {
+		for i, match := range jobMatches {
+			if !reIndustryJobContext.MatchString(match[1] + match[4]) {
+				rest[i] = input[i]
+				delete(jobMatches, i)
+			}
+		}
+	}
+	if len(jobMatches) == 0 {
+		for i := range jobsHeaderMatches {
+			rest[i] = input[i]
+		}
+		jobsHeaderMatches = nil
+	}
+
 	industry.lines = append(industry.lines, regexMatchedLines(matches)...)
+	industry.lines = append(industry.lines, regexMatchedLines(materialMatches)...)
+	industry.lines = append(industry.lines, regexMatchedLines(jobMatches)...)
+	industry.lines = append(industry.lines, regexMatchedLines(jobsHeaderMatches)...)
+
+	// Only treat a header as part of the result when there is something under it
+	if len(materialMatches) > 0 {
+		var headerMatches map[int][]string
+		headerMatches, rest = regexParseLines(reIndustryMaterialsHeader, rest)
+		industry.lines = append(industry.lines, regexMatchedLines(headerMatches)...)
+	}
+	sort.Ints(industry.lines)
 
 	// collect items
 	matchgroup := make(map[IndustryItem]int64)
@@ -37,11 +121,31 @@ func ParseIndustry(input Input) (ParserResult, Input) {
 		matchgroup[IndustryItem{Name: match[1]}] += ToInt(match[2])
 	}
 
+	// material lists are a shopping list of whatever is missing
+	for _, match := range materialMatches {
+		missing := ToInt(match[4])
+		if missing == 0 {
+			continue
+		}
+		matchgroup[IndustryItem{Name: CleanTypeName(match[1])}] += missing
+	}
+
+	jobRuns := make(map[string]int64)
+	for _, match := range jobMatches {
+		if reIndustryNonManufacturingJob.MatchString(match[1] + match[4]) {
+			continue
+		}
+		jobRuns[CleanTypeName(match[2])] += ToInt(match[3])
+	}
+
 	// add items w/totals
 	for item, quantity := range matchgroup {
 		item.Quantity = quantity
 		industry.Items = append(industry.Items, item)
 	}
+	for name, runs := range jobRuns {
+		industry.Items = append(industry.Items, IndustryItem{Name: name, Quantity: runs, Runs: runs})
+	}
 
 	sort.Slice(industry.Items, func(i, j int) bool {
 		return fmt.Sprintf("%v", industry.Items[i]) < fmt.Sprintf("%v", industry.Items[j])
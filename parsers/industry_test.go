@@ -1,5 +1,11 @@
 package parsers
 
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
 var industryTestCases = []Case{
 	{
 		"Basic",
@@ -27,5 +33,77 @@ Nocxium (51 Units)`,
 			lines: []int{0}},
 		Input{},
 		true,
+	}, {
+		"Material requirements",
+		`Item	Required	Available	Missing
+Tritanium	10,000	4,000	6,000
+Pyerite	2000	2000	0
+Mexallon	500	0	500`,
+		&Industry{
+			Items: []IndustryItem{
+				{Name: "Mexallon", Quantity: 500},
+				{Name: "Tritanium", Quantity: 6000},
+			},
+			lines: []int{0, 1, 2, 3}},
+		Input{},
+		true,
+	}, {
+		"Jobs",
+		`In Progress	Manufacturing	Scourge Light Missile Blueprint	10	Some Builder	Jita IV - Moon 4 - Caldari Navy Assembly Plant
+Ready	Manufacturing	Scourge Light Missile Blueprint	5	Some Builder	Jita IV - Moon 4 - Caldari Navy Assembly Plant
+In Progress	Copying	Rifter Blueprint	3	Some Builder	Jita IV - Moon 4 - Caldari Navy Assembly Plant
+Ready	Reactions	Carbon Fiber Reaction Formula	20`,
+		&Industry{
+			Items: []IndustryItem{
+				{Name: "Carbon Fiber", Quantity: 20, Runs: 20},
+				{Name: "Scourge Light Missile", Quantity: 15, Runs: 15},
+			},
+			lines: []int{0, 1, 2, 3}},
+		Input{},
+		true,
+	}, {
+		"Jobs below a header",
+		`Status	Blueprint	Runs	Installer
+Rifter Blueprint	3	Some Builder
+Carbon Fiber Reaction Formula	20	Some Builder`,
+		&Industry{
+			Items: []IndustryItem{
+				{Name: "Carbon Fiber", Quantity: 20, Runs: 20},
+				{Name: "Rifter", Quantity: 3, Runs: 3},
+			},
+			lines: []int{0, 1, 2}},
+		Input{},
+		false,
+	}, {
+		"Blueprint without job context",
+		`Rifter Blueprint	3`,
+		&Industry{lines: nil},
+		Input{0: "Rifter Blueprint\t3"},
+		false,
+	}, {
+		"Localized units",
+		"Тританий (4\u00a0662 ед.)\nPyérite (1857 unités)\nトリタニウム (12 個)",
+		&Industry{
+			Items: []IndustryItem{
+				{Name: "Pyérite", Quantity: 1857},
+				{Name: "Тританий", Quantity: 4662},
+				{Name: "トリタニウム", Quantity: 12},
+			},
+			lines: []int{0, 1, 2}},
+		Input{},
+		false,
 	},
 }
+
+// A blueprint with a quantity is a blueprint, not a job, unless the line says it is a job
+func TestBlueprintIsNotAJob(t *testing.T) {
+	result, rest := NewMultiParser(AllParsers())(StringToInput("Rifter Blueprint\t3"))
+	assert.Equal(t, Input{}, rest)
+
+	results := result.(*MultiParserResult).Results
+	assert.Len(t, results, 1)
+	assert.Equal(t, &AssetList{
+		Items: []AssetItem{{Name: "Rifter Blueprint", Quantity: 3}},
+		lines: []int{0},
+	}, results[0])
+}
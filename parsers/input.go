@@ -2,6 +2,8 @@ package parsers
 
 import (
 	"bytes"
+	"regexp"
+	"sort"
 	"strings"
 )
 
@@ -15,11 +17,26 @@ func StringsToInput(lines []string) Input {
 	return m
 }
 
+// Clients in some languages group digits with (narrow) no-break spaces, like "1 234 567"
+var reDigitGroupSpace = regexp.MustCompile(`(\d)[\x{a0}\x{202f}](\d\d\d)`)
+
 func StringToInput(s string) Input {
 	s = strings.Replace(s, "\r", "", -1)
+	s = removeDigitGroupSpaces(s)
 	return StringsToInput(strings.Split(s, "\n"))
 }
 
+func removeDigitGroupSpaces(s string) string {
+	// Matches can't overlap, so every pass only removes every other separator of a number
+	for {
+		replaced := reDigitGroupSpace.ReplaceAllString(s, "$1$2")
+		if replaced == s {
+			return s
+		}
+		s = replaced
+	}
+}
+
 func (m Input) Strings() []string {
 	keys := make([]int, 0)
 	for k := range m {
@@ -43,3 +60,13 @@ func (m Input) String() string {
 	}
 	return buffer.String()
 }
+
+// LineNumbers returns the line numbers of the input in order
+func (m Input) LineNumbers() []int {
+	keys := make([]int, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Ints(keys)
+	return keys
+}
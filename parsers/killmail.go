@@ -38,6 +38,10 @@ var reKillmailPlayerLine = regexp.MustCompile(`^([\w\s]+): ([\S ]+)$`)
 var reKillmailInvolvedLine = regexp.MustCompile(`^([\w ]+): ([\S ]+?)( \(laid the final blow\))?$`)
 var reKillmailItemLine = regexp.MustCompile(`^([\w '-]+?)(?:, Qty: (\d+))?(?: \(([\w ]+)\))?$`)
 
+func init() {
+	RegisterParser("killmail", 10, ParseKillmail)
+}
+
 func ParseKillmail(input Input) (ParserResult, Input) {
 	killmail := &Killmail{}
 	if len(input) == 0 {
@@ -0,0 +1,320 @@
+package parsers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/evepraisal/go-evepraisal/typedb"
+)
+
+// ESIKillmails holds one or more killmails in the JSON format that ESI uses
+type ESIKillmails struct {
+	Killmails []ESIKillmail
+	lines     []int
+}
+
+func (r *ESIKillmails) Name() string {
+	return "killmail"
+}
+
+func (r *ESIKillmails) Lines() []int {
+	return r.lines
+}
+
+type ESIKillmail struct {
+	KillmailID    int64
+	Time          string
+	SolarSystemID int64
+	Ship          string
+	Destroyed     []KillmailItem
+	Dropped       []KillmailItem
+}
+
+// KillmailFetchFunc returns the ESI JSON of a killmail. An empty hash means that the hash has to be looked up first.
+type KillmailFetchFunc func(killmailID int64, hash string) ([]byte, error)
+
+type esiKillmail struct {
+	KillmailID    int64  `json:"killmail_id"`
+	KillmailTime  string `json:"killmail_time"`
+	SolarSystemID int64  `json:"solar_system_id"`
+	Victim        *struct {
+		ShipTypeID int64             `json:"ship_type_id"`
+		Items      []esiKillmailItem `json:"items"`
+	} `json:"victim"`
+}
+
+type esiKillmailItem struct {
+	ItemTypeID        int64             `json:"item_type_id"`
+	Flag              int64             `json:"flag"`
+	QuantityDestroyed int64             `json:"quantity_destroyed"`
+	QuantityDropped   int64             `json:"quantity_dropped"`
+	Items             []esiKillmailItem `json:"items"`
+}
+
+var reESIKillmailLink = regexp.MustCompile(`^\s*https?://\S+/killmails/(\d+)/([0-9a-fA-F]+)/?\s*$`)
+var reZKillboardLink = regexp.MustCompile(`^\s*https?://(?:www\.)?zkillboard\.com/kill/(\d+)/?\s*$`)
+
+// killmailFlagLocations maps inventory flags to the locations used in the in-game killmail text. Fitted
+// modules and loaded charges don't have a location there, so they don't have one here either.
+var killmailFlagLocations = map[int64]string{
+	5:   "Cargo",
+	87:  "Drone Bay",
+	89:  "Implant",
+	90:  "Ship Hangar",
+	133: "Fuel Bay",
+	134: "Ore Hold",
+	135: "Gas Hold",
+	136: "Mineral Hold",
+	137: "Salvage Hold",
+	138: "Ship Hold",
+	139: "Small Ship Hold",
+	140: "Medium Ship Hold",
+	141: "Large Ship Hold",
+	142: "Industrial Ship Hold",
+	143: "Ammo Hold",
+	148: "Command Center Hold",
+	149: "Planetary Commodities Hold",
+	151: "Material Bay",
+	154: "Quafe Bay",
+	155: "Fleet Hangar",
+	158: "Fighter Bay",
+	159: "Fighter Tubes",
+	160: "Fighter Tubes",
+	161: "Fighter Tubes",
+	162: "Fighter Tubes",
+	163: "Fighter Tubes",
+}
+
+const (
+	// maxKillmailLinks is how many different killmails are fetched for a single paste. Links beyond that are
+	// left unparsed.
+	maxKillmailLinks = 10
+	// killmailFetchTimeout is how long fetching all of the killmails of a single paste may take
+	killmailFetchTimeout = 10 * time.Second
+)
+
+var errKillmailFetchTimeout = errors.New("ran out of time fetching killmails")
+
+type KillmailJSONParser struct {
+	typeDB       typedb.TypeDB
+	fetch        KillmailFetchFunc
+	maxLinks     int
+	fetchTimeout time.Duration
+}
+
+func init() {
+	Register("killmail_json", 20, func(deps ParserDeps) Parser {
+		if deps.TypeDB == nil {
+			return nil
+		}
+		return NewKillmailJSONParser(deps.TypeDB, deps.FetchKillmail)
+	})
+}
+
+// NewKillmailJSONParser returns a parser for ESI killmail JSON. If fetch is set, killmail links from ESI and
+// zKillboard are resolved with it as well.
+func NewKillmailJSONParser(typeDB typedb.TypeDB, fetch KillmailFetchFunc) Parser {
+	p := &KillmailJSONParser{typeDB: typeDB, fetch: fetch, maxLinks: maxKillmailLinks, fetchTimeout: killmailFetchTimeout}
+	return p.Parse
+}
+
+func (p *KillmailJSONParser) Parse(input Input) (ParserResult, Input) {
+	if len(input) == 0 {
+		return nil, input
+	}
+
+	lineNumbers := input.LineNumbers()
+	first := strings.TrimSpace(input[lineNumbers[0]])
+	if strings.HasPrefix(first, "{") || strings.HasPrefix(first, "[") {
+		return p.parseJSON(input, lineNumbers)
+	}
+
+	if p.fetch == nil {
+		return nil, input
+	}
+	return p.parseLinks(input, lineNumbers)
+}
+
+// parseJSON only succeeds if the whole input is a single killmail or a list of killmails
+func (p *KillmailJSONParser) parseJSON(input Input, lineNumbers []int) (ParserResult, Input) {
+	lines := make([]string, len(lineNumbers))
+	for i, lineNumber := range lineNumbers {
+		lines[i] = input[lineNumber]
+	}
+	raw := []byte(strings.Join(lines, "\n"))
+
+	var killmails []esiKillmail
+	if strings.HasPrefix(strings.TrimSpace(lines[0]), "[") {
+		if err := json.Unmarshal(raw, &killmails); err != nil {
+			return nil, input
+		}
+	} else {
+		var killmail esiKillmail
+		if err := json.Unmarshal(raw, &killmail); err != nil {
+			return nil, input
+		}
+		killmails = []esiKillmail{killmail}
+	}
+
+	result := &ESIKillmails{}
+	for _, killmail := range killmails {
+		if killmail.Victim == nil {
+			return nil, input
+		}
+		result.Killmails = append(result.Killmails, p.toKillmail(killmail))
+	}
+	if len(result.Killmails) == 0 {
+		return nil, input
+	}
+	result.lines = lineNumbers
+	return result, Input{}
+}
+
+// parseLinks fetches the killmails that are linked to. Every killmail is only fetched (and counted) once, no
+// matter how often it is linked.
+func (p *KillmailJSONParser) parseLinks(input Input, lineNumbers []int) (ParserResult, Input) {
+	result := &ESIKillmails{}
+	rest := make(Input)
+	deadline := time.Now().Add(p.fetchTimeout)
+	fetched := make(map[int64]bool)
+	for _, lineNumber := range lineNumbers {
+		line := input[lineNumber]
+
+		var (
+			killmailID int64
+			hash       string
+		)
+		if match := reESIKillmailLink.FindStringSubmatch(line); len(match) > 0 {
+			killmailID, _ = strconv.ParseInt(match[1], 10, 64)
+			hash = match[2]
+		} else if match := reZKillboardLink.FindStringSubmatch(line); len(match) > 0 {
+			killmailID, _ = strconv.ParseInt(match[1], 10, 64)
+		} else {
+			rest[lineNumber] = line
+			continue
+		}
+
+		if ok, seen := fetched[killmailID]; seen {
+			if ok {
+				result.lines = append(result.lines, lineNumber)
+			} else {
+				rest[lineNumber] = line
+			}
+			continue
+		}
+		if len(fetched) >= p.maxLinks {
+			rest[lineNumber] = line
+			continue
+		}
+		fetched[killmailID] = false
+
+		raw, err := p.fetchBefore(deadline, killmailID, hash)
+		if err != nil {
+			log.Printf("WARNING: unable to fetch killmail %d: %s", killmailID, err)
+			rest[lineNumber] = line
+			continue
+		}
+
+		var killmail esiKillmail
+		err = json.Unmarshal(raw, &killmail)
+		if err != nil || killmail.Victim == nil {
+			log.Printf("WARNING: unable to read killmail %d: %v", killmailID, err)
+			rest[lineNumber] = line
+			continue
+		}
+
+		fetched[killmailID] = true
+		result.Killmails = append(result.Killmails, p.toKillmail(killmail))
+		result.lines = append(result.lines, lineNumber)
+	}
+	return result, rest
+}
+
+// fetchBefore fetches a killmail, but gives up once the deadline has passed
+func (p *KillmailJSONParser) fetchBefore(deadline time.Time, killmailID int64, hash string) ([]byte, error) {
+	remaining := time.Until(deadline)
+	if remaining <= 0 {
+		return nil, errKillmailFetchTimeout
+	}
+
+	type fetchResult struct {
+		raw []byte
+		err error
+	}
+	done := make(chan fetchResult, 1)
+	go func() {
+		raw, err := p.fetch(killmailID, hash)
+		done <- fetchResult{raw, err}
+	}()
+
+	timer := time.NewTimer(remaining)
+	defer timer.Stop()
+	select {
+	case r := <-done:
+		return r.raw, r.err
+	case <-timer.C:
+		return nil, errKillmailFetchTimeout
+	}
+}
+
+func (p *KillmailJSONParser) toKillmail(killmail esiKillmail) ESIKillmail {
+	km := ESIKillmail{
+		KillmailID:    killmail.KillmailID,
+		Time:          killmail.KillmailTime,
+		SolarSystemID: killmail.SolarSystemID,
+		Ship:          p.typeName(killmail.Victim.ShipTypeID),
+	}
+	if km.Ship != "" {
+		// The hull never drops
+		km.Destroyed = append(km.Destroyed, KillmailItem{Name: km.Ship, Quantity: 1, Location: "Ship"})
+	}
+	p.addItems(&km, killmail.Victim.Items, false)
+
+	sortKillmailItems(km.Destroyed)
+	sortKillmailItems(km.Dropped)
+	return km
+}
+
+func (p *KillmailJSONParser) addItems(km *ESIKillmail, items []esiKillmailItem, inContainer bool) {
+	for _, item := range items {
+		name := p.typeName(item.ItemTypeID)
+		if name == "" {
+			continue
+		}
+
+		location := killmailFlagLocations[item.Flag]
+		if inContainer {
+			location = "In Container"
+		}
+
+		if item.QuantityDestroyed > 0 {
+			km.Destroyed = append(km.Destroyed, KillmailItem{Name: name, Quantity: item.QuantityDestroyed, Location: location})
+		}
+		if item.QuantityDropped > 0 {
+			km.Dropped = append(km.Dropped, KillmailItem{Name: name, Quantity: item.QuantityDropped, Location: location})
+		}
+		p.addItems(km, item.Items, true)
+	}
+}
+
+func (p *KillmailJSONParser) typeName(typeID int64) string {
+	t, ok := p.typeDB.GetTypeByID(typeID)
+	if !ok {
+		log.Printf("WARNING: unknown type in killmail: %d", typeID)
+		return ""
+	}
+	return t.Name
+}
+
+func sortKillmailItems(items []KillmailItem) {
+	sort.Slice(items, func(i, j int) bool {
+		return fmt.Sprintf("%v", items[i]) < fmt.Sprintf("%v", items[j])
+	})
+}
@@ -0,0 +1,161 @@
+package parsers
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/evepraisal/go-evepraisal/typedb"
+	"github.com/stretchr/testify/assert"
+)
+
+var killmailJSONTypes = []typedb.EveType{
+	{ID: 587, Name: "Rifter"},
+	{ID: 2873, Name: "125mm Gatling AutoCannon II"},
+	{ID: 21894, Name: "Republic Fleet EMP S"},
+	{ID: 2048, Name: "Damage Control II"},
+	{ID: 2486, Name: "Warrior I"},
+	{ID: 3467, Name: "Small Secure Container"},
+	{ID: 34, Name: "Tritanium"},
+}
+
+const killmailJSONFixture = `{
+  "killmail_id": 72410059,
+  "killmail_time": "2018-09-01T12:34:56Z",
+  "solar_system_id": 30002187,
+  "victim": {
+    "character_id": 90000001,
+    "ship_type_id": 587,
+    "items": [
+      {"item_type_id": 2873, "flag": 27, "quantity_destroyed": 1, "singleton": 0},
+      {"item_type_id": 21894, "flag": 27, "quantity_dropped": 100, "singleton": 0},
+      {"item_type_id": 2048, "flag": 11, "quantity_dropped": 1, "singleton": 0},
+      {"item_type_id": 2486, "flag": 87, "quantity_destroyed": 2, "singleton": 0},
+      {"item_type_id": 99999, "flag": 5, "quantity_destroyed": 1, "singleton": 0},
+      {"item_type_id": 3467, "flag": 5, "quantity_dropped": 1, "singleton": 0, "items": [
+        {"item_type_id": 34, "flag": 0, "quantity_destroyed": 500, "quantity_dropped": 250, "singleton": 0}
+      ]}
+    ]
+  },
+  "attackers": []
+}`
+
+var killmailJSONExpected = ESIKillmail{
+	KillmailID:    72410059,
+	Time:          "2018-09-01T12:34:56Z",
+	SolarSystemID: 30002187,
+	Ship:          "Rifter",
+	Destroyed: []KillmailItem{
+		{Name: "125mm Gatling AutoCannon II", Quantity: 1, Location: ""},
+		{Name: "Rifter", Quantity: 1, Location: "Ship"},
+		{Name: "Tritanium", Quantity: 500, Location: "In Container"},
+		{Name: "Warrior I", Quantity: 2, Location: "Drone Bay"},
+	},
+	Dropped: []KillmailItem{
+		{Name: "Damage Control II", Quantity: 1, Location: ""},
+		{Name: "Republic Fleet EMP S", Quantity: 100, Location: ""},
+		{Name: "Small Secure Container", Quantity: 1, Location: "Cargo"},
+		{Name: "Tritanium", Quantity: 250, Location: "In Container"},
+	},
+}
+
+func fixtureKillmailFetch(killmailID int64, hash string) ([]byte, error) {
+	if killmailID != 72410059 {
+		return nil, fmt.Errorf("not found")
+	}
+	return []byte(killmailJSONFixture), nil
+}
+
+var KillmailJSONParserCases = []struct {
+	name   string
+	in     string
+	result ParserResult
+	left   Input
+}{
+	{
+		"ESI JSON",
+		killmailJSONFixture,
+		&ESIKillmails{
+			Killmails: []ESIKillmail{killmailJSONExpected},
+			lines:     []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 18, 19},
+		},
+		Input{},
+	}, {
+		"Links, with the same killmail only counted once",
+		`https://esi.evetech.net/latest/killmails/72410059/0123456789abcdef0123456789abcdef01234567/
+https://zkillboard.com/kill/72410059/
+https://zkillboard.com/kill/1/
+Tritanium 5`,
+		&ESIKillmails{
+			Killmails: []ESIKillmail{killmailJSONExpected},
+			lines:     []int{0, 1},
+		},
+		Input{2: "https://zkillboard.com/kill/1/", 3: "Tritanium 5"},
+	}, {
+		"Invalid JSON",
+		`{"killmail_id": 1,`,
+		nil,
+		Input{0: `{"killmail_id": 1,`},
+	},
+}
+
+func TestKillmailJSONParser(rt *testing.T) {
+	for _, c := range KillmailJSONParserCases {
+		rt.Run(c.name, func(t *testing.T) {
+			db := &StaticTypeDB{
+				typeNameMap: make(map[string]typedb.EveType),
+				typeIDMap:   make(map[int64]typedb.EveType),
+			}
+			for _, t := range killmailJSONTypes {
+				db.PutType(t)
+			}
+			result, rest := NewKillmailJSONParser(db, fixtureKillmailFetch)(StringToInput(c.in))
+			assert.Equal(t, c.result, result, "results should be the same")
+			assert.Equal(t, c.left, rest, "the rest should be the same")
+		})
+	}
+}
+
+func TestKillmailLinkLimits(t *testing.T) {
+	db := &StaticTypeDB{
+		typeNameMap: make(map[string]typedb.EveType),
+		typeIDMap:   make(map[int64]typedb.EveType),
+	}
+	for _, t := range killmailJSONTypes {
+		db.PutType(t)
+	}
+
+	var mu sync.Mutex
+	var fetched []int64
+	fetch := func(delay time.Duration) KillmailFetchFunc {
+		return func(killmailID int64, hash string) ([]byte, error) {
+			mu.Lock()
+			fetched = append(fetched, killmailID)
+			mu.Unlock()
+			time.Sleep(delay)
+			return []byte(killmailJSONFixture), nil
+		}
+	}
+	links := StringToInput(`https://zkillboard.com/kill/1/
+https://zkillboard.com/kill/2/
+https://zkillboard.com/kill/1/
+https://zkillboard.com/kill/3/`)
+
+	p := &KillmailJSONParser{typeDB: db, fetch: fetch(0), maxLinks: 2, fetchTimeout: time.Second}
+	result, rest := p.Parse(links)
+	assert.Equal(t, []int64{1, 2}, fetched)
+	assert.Len(t, result.(*ESIKillmails).Killmails, 2)
+	assert.Equal(t, []int{0, 1, 2}, result.Lines())
+	assert.Equal(t, Input{3: "https://zkillboard.com/kill/3/"}, rest)
+
+	// Once the time is up the remaining links aren't fetched anymore
+	fetched = nil
+	p = &KillmailJSONParser{typeDB: db, fetch: fetch(100 * time.Millisecond), maxLinks: 10, fetchTimeout: 50 * time.Millisecond}
+	result, rest = p.Parse(links)
+	mu.Lock()
+	assert.Equal(t, []int64{1}, fetched)
+	mu.Unlock()
+	assert.Empty(t, result.Lines())
+	assert.Equal(t, links, rest)
+}
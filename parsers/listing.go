@@ -29,6 +29,27 @@ var reListing2 = regexp.MustCompile(`^([\S ]+?) (?:x|X)? ?([\d,'\.]+)$`)
 var reListing3 = regexp.MustCompile(`^([\S ]+)$`)
 var reListingWithAmmo = regexp.MustCompile(`^([\S ]+), ?([a-zA-Z][\S ]+)$`)
 
+// LineConfidence is lower for lines that are just a name or a list of names, since almost any text looks like that
+func (r *Listing) LineConfidence(text string) (float64, string) {
+	switch {
+	case reListingWithAmmo.MatchString(text):
+		return 0.5, "names a module and its charge"
+	case reListing.MatchString(text), reListing2.MatchString(text):
+		return defaultConfidence["listing"], "has a name and a quantity"
+	default:
+		return 0.45, "only has a name"
+	}
+}
+
+func init() {
+	Register("listing", 230, func(deps ParserDeps) Parser {
+		if deps.TypeDB != nil {
+			return NewContextListingParser(deps.TypeDB)
+		}
+		return ParseListing
+	})
+}
+
 func ParseListing(input Input) (ParserResult, Input) {
 	listing := &Listing{}
 
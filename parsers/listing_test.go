@@ -67,5 +67,17 @@ Tritanium X 1`,
 		},
 		Input{},
 		false,
+	}, {
+		"with no-break space digit groups",
+		"1\u00a0234\u00a0567 Tritanium\nPyerite 12\u202f000",
+		&Listing{
+			Items: []ListingItem{
+				{Name: "Pyerite", Quantity: 12000},
+				{Name: "Tritanium", Quantity: 1234567},
+			},
+			lines: []int{0, 1},
+		},
+		Input{},
+		false,
 	},
 }
@@ -0,0 +1,34 @@
+package parsers
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Words that the EVE client shows around type names, in English, German, French, Russian, Japanese and Chinese
+var (
+	localizedUnits     = []string{"Units", "Unit", "Einheiten", "Einheit", "unités", "unité", "ед.", "個", "个"}
+	localizedRuns      = []string{"runs", "run", "Runs", "Run", "Durchläufe", "Durchlauf", "séries", "série", "циклов", "цикла", "цикл", "回", "流程"}
+	localizedRouted    = []string{"Routed", "Geroutet", "Acheminé", "Проложен маршрут", "ルート設定済み", "已设置路线"}
+	localizedNotRouted = []string{"Not routed", "Nicht geroutet", "Non acheminé", "Маршрут не проложен", "ルート未設定", "未设置路线"}
+)
+
+// keywordPattern returns a non-capturing regex group that matches any of the keywords
+func keywordPattern(keywords ...[]string) string {
+	var quoted []string
+	for _, list := range keywords {
+		for _, keyword := range list {
+			quoted = append(quoted, regexp.QuoteMeta(keyword))
+		}
+	}
+	return `(?:` + strings.Join(quoted, "|") + `)`
+}
+
+func isKeyword(keywords []string, s string) bool {
+	for _, keyword := range keywords {
+		if strings.EqualFold(keyword, s) {
+			return true
+		}
+	}
+	return false
+}
@@ -28,6 +28,10 @@ type LootItem struct {
 
 var reLootHistory = regexp.MustCompile(`(\d\d:\d\d:\d\d) ([\S ]+) has looted ([\d,'\.\ ]+) x ([\S ]+)$`)
 
+func init() {
+	RegisterParser("loot_history", 80, ParseLootHistory)
+}
+
 func ParseLootHistory(input Input) (ParserResult, Input) {
 	lootHistory := &LootHistory{}
 	matches, rest := regexParseLines(reLootHistory, input)
@@ -0,0 +1,98 @@
+package parsers
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+type MarketOrders struct {
+	Items []MarketOrder
+	lines []int
+}
+
+func (r *MarketOrders) Name() string {
+	return "market_orders"
+}
+
+func (r *MarketOrders) Lines() []int {
+	return r.lines
+}
+
+type MarketOrder struct {
+	Name          string
+	Quantity      int64
+	TotalQuantity int64
+	Price         float64
+	Station       string
+	Range         string
+	Expires       string
+	OrderType     string
+}
+
+var reMarketOrder = regexp.MustCompile(strings.Join([]string{
+	`^([\S ]+)\t`,                   // type
+	`([\d,'\.\ ]+)/([\d,'\.\ ]+)\t`, // quantity remaining/total
+	`([\d,'\.\ ]+) ?ISK\t`,          // price
+	`([\S ]+)\t`,                    // station
+	`([\S ]*)\t`,                    // range
+	`([\S ]+?)`,                     // expires
+	`(?:\t(Buy|Sell|buy|sell|Buying|Selling))?$`, // buy/sell
+}, ""))
+
+var reMarketOrderHeader = regexp.MustCompile(`^(?i)\s*(selling|buying|sell orders|buy orders)\s*$`)
+
+func init() {
+	RegisterParser("market_orders", 110, ParseMarketOrders)
+}
+
+// ParseMarketOrders parses the "My Orders" window and corporation market order exports. The order type
+// comes from a buy/sell column if there is one, otherwise from the last "Selling" or "Buying" header.
+func ParseMarketOrders(input Input) (ParserResult, Input) {
+	orders := &MarketOrders{}
+	matches, rest := regexParseLines(reMarketOrder, input)
+	if len(matches) == 0 {
+		return orders, rest
+	}
+
+	headers, rest := regexParseLines(reMarketOrderHeader, rest)
+	orders.lines = append(regexMatchedLines(matches), regexMatchedLines(headers)...)
+	sort.Ints(orders.lines)
+
+	orderType := "sell"
+	for _, line := range orders.lines {
+		if header, ok := headers[line]; ok {
+			orderType = marketOrderType(header[1])
+			continue
+		}
+
+		match := matches[line]
+		order := MarketOrder{
+			Name:          CleanTypeName(match[1]),
+			Quantity:      ToInt(match[2]),
+			TotalQuantity: ToInt(match[3]),
+			Price:         ToDecimal(match[4]),
+			Station:       match[5],
+			Range:         match[6],
+			Expires:       match[7],
+			OrderType:     orderType,
+		}
+		if match[8] != "" {
+			order.OrderType = marketOrderType(match[8])
+		}
+		orders.Items = append(orders.Items, order)
+	}
+
+	sort.Slice(orders.Items, func(i, j int) bool {
+		return fmt.Sprintf("%v", orders.Items[i]) < fmt.Sprintf("%v", orders.Items[j])
+	})
+	return orders, rest
+}
+
+func marketOrderType(s string) string {
+	if strings.HasPrefix(strings.ToLower(s), "buy") {
+		return "buy"
+	}
+	return "sell"
+}
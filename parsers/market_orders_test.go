@@ -0,0 +1,50 @@
+package parsers
+
+var marketOrdersTestCases = []Case{
+	{
+		"Sell order",
+		`Tritanium	1,500/2,000	5.50 ISK	Jita IV - Moon 4 - Caldari Navy Assembly Plant	Station	89d 23h 59m 59s`,
+		&MarketOrders{
+			Items: []MarketOrder{
+				{Name: "Tritanium", Quantity: 1500, TotalQuantity: 2000, Price: 5.5, Station: "Jita IV - Moon 4 - Caldari Navy Assembly Plant", Range: "Station", Expires: "89d 23h 59m 59s", OrderType: "sell"},
+			},
+			lines: []int{0},
+		},
+		Input{},
+		true,
+	}, {
+		"Buy/sell column",
+		`Tritanium	1,500/2,000	5.50 ISK	Jita IV - Moon 4 - Caldari Navy Assembly Plant	Region	89d 23h	Buy
+Pyerite	10/10	12.00 ISK	Amarr VIII (Oris) - Emperor Family Academy	Station	3d 2h	Sell`,
+		&MarketOrders{
+			Items: []MarketOrder{
+				{Name: "Pyerite", Quantity: 10, TotalQuantity: 10, Price: 12, Station: "Amarr VIII (Oris) - Emperor Family Academy", Range: "Station", Expires: "3d 2h", OrderType: "sell"},
+				{Name: "Tritanium", Quantity: 1500, TotalQuantity: 2000, Price: 5.5, Station: "Jita IV - Moon 4 - Caldari Navy Assembly Plant", Range: "Region", Expires: "89d 23h", OrderType: "buy"},
+			},
+			lines: []int{0, 1},
+		},
+		Input{},
+		true,
+	}, {
+		"Selling and buying sections",
+		`Selling
+Rifter	1/1	450,000.00 ISK	Rens VI - Moon 8 - Brutor Tribe Treasury	Station	30d
+Buying
+Rifter	3/5	400,000.00 ISK	Rens VI - Moon 8 - Brutor Tribe Treasury	5 Jumps	30d`,
+		&MarketOrders{
+			Items: []MarketOrder{
+				{Name: "Rifter", Quantity: 1, TotalQuantity: 1, Price: 450000, Station: "Rens VI - Moon 8 - Brutor Tribe Treasury", Range: "Station", Expires: "30d", OrderType: "sell"},
+				{Name: "Rifter", Quantity: 3, TotalQuantity: 5, Price: 400000, Station: "Rens VI - Moon 8 - Brutor Tribe Treasury", Range: "5 Jumps", Expires: "30d", OrderType: "buy"},
+			},
+			lines: []int{0, 1, 2, 3},
+		},
+		Input{},
+		true,
+	}, {
+		"Headers without orders",
+		`Selling`,
+		&MarketOrders{},
+		Input{0: "Selling"},
+		false,
+	},
+}
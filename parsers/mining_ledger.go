@@ -0,0 +1,147 @@
+package parsers
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+type MiningLedger struct {
+	Items []MiningLedgerItem
+	lines []int
+}
+
+func (r *MiningLedger) Name() string {
+	return "mining_ledger"
+}
+
+func (r *MiningLedger) Lines() []int {
+	return r.lines
+}
+
+type MiningLedgerItem struct {
+	Date        string
+	PlayerName  string
+	Corporation string
+	Name        string
+	Quantity    int64
+	Volume      float64
+	Value       float64
+	SolarSystem string
+}
+
+// MiningLedgerTotal is the sum of all ledger entries for a single character or ore type
+type MiningLedgerTotal struct {
+	Name     string
+	Quantity int64
+	Volume   float64
+	Value    float64
+}
+
+// TotalsByCharacter returns the mined totals for each character, sorted by name
+func (r *MiningLedger) TotalsByCharacter() []MiningLedgerTotal {
+	return r.totals(func(item MiningLedgerItem) string { return item.PlayerName })
+}
+
+// TotalsByOre returns the mined totals for each ore type, sorted by name
+func (r *MiningLedger) TotalsByOre() []MiningLedgerTotal {
+	return r.totals(func(item MiningLedgerItem) string { return item.Name })
+}
+
+func (r *MiningLedger) totals(key func(item MiningLedgerItem) string) []MiningLedgerTotal {
+	totalMap := make(map[string]MiningLedgerTotal)
+	for _, item := range r.Items {
+		total := totalMap[key(item)]
+		total.Name = key(item)
+		total.Quantity += item.Quantity
+		total.Volume += item.Volume
+		total.Value += item.Value
+		totalMap[key(item)] = total
+	}
+
+	totals := make([]MiningLedgerTotal, 0, len(totalMap))
+	for _, total := range totalMap {
+		totals = append(totals, total)
+	}
+	sort.Slice(totals, func(i, j int) bool {
+		return totals[i].Name < totals[j].Name
+	})
+	return totals
+}
+
+const (
+	reLedgerDate   = `^(\d\d\d\d[\.\-/]\d\d[\.\-/]\d\d(?: \d\d:\d\d(?::\d\d)?)?)\t`
+	reLedgerNumber = `([\d,'\.\ ` + "\xc2\xa0" + `]+?)`
+)
+
+var reMiningLedger = regexp.MustCompile(strings.Join([]string{
+	reLedgerDate,                   // date
+	`([\S ]+)\t`,                   // ore type
+	reLedgerNumber + `\t`,          // quantity
+	reLedgerNumber + ` ?(?:m3)?\t`, // volume
+	reLedgerNumber + ` ?(?:ISK)?`,  // estimated value
+	`(?:\t([\S ]*))?$`,             // solar system
+}, ""))
+
+var reMoonMiningLedger = regexp.MustCompile(strings.Join([]string{
+	reLedgerDate,                   // date
+	`([\S ]+)\t`,                   // character
+	`(?:([\S ]*)\t)?`,              // corporation
+	`([\S ]+)\t`,                   // ore type
+	reLedgerNumber + `\t`,          // quantity
+	reLedgerNumber + ` ?(?:m3)?\t`, // volume
+	reLedgerNumber + ` ?(?:ISK)?$`, // estimated value
+}, ""))
+
+func init() {
+	RegisterParser("mining_ledger", 90, ParseMiningLedger)
+	RegisterParser("moon_mining_ledger", 100, ParseMoonMiningLedger)
+}
+
+// ParseMiningLedger parses the personal mining ledger
+func ParseMiningLedger(input Input) (ParserResult, Input) {
+	ledger := &MiningLedger{}
+	matches, rest := regexParseLines(reMiningLedger, input)
+	ledger.lines = regexMatchedLines(matches)
+	for _, match := range matches {
+		ledger.Items = append(ledger.Items,
+			MiningLedgerItem{
+				Date:        match[1],
+				Name:        CleanTypeName(match[2]),
+				Quantity:    ToInt(match[3]),
+				Volume:      ToDecimal(match[4]),
+				Value:       ToDecimal(match[5]),
+				SolarSystem: match[6],
+			})
+	}
+
+	sort.Slice(ledger.Items, func(i, j int) bool {
+		return fmt.Sprintf("%v", ledger.Items[i]) < fmt.Sprintf("%v", ledger.Items[j])
+	})
+	return ledger, rest
+}
+
+// ParseMoonMiningLedger parses the corporation moon mining (observer) ledger, which has a row per character
+func ParseMoonMiningLedger(input Input) (ParserResult, Input) {
+	ledger := &MiningLedger{}
+	matches, rest := regexParseLines(reMoonMiningLedger, input)
+	ledger.lines = regexMatchedLines(matches)
+	for _, match := range matches {
+		ledger.Items = append(ledger.Items,
+			MiningLedgerItem{
+				Date:        match[1],
+				PlayerName:  match[2],
+				Corporation: match[3],
+				Name:        CleanTypeName(match[4]),
+				Quantity:    ToInt(match[5]),
+				Volume:      ToDecimal(match[6]),
+				Value:       ToDecimal(match[7]),
+			})
+	}
+
+	sort.Slice(ledger.Items, func(i, j int) bool {
+		return fmt.Sprintf("%v", ledger.Items[i]) < fmt.Sprintf("%v", ledger.Items[j])
+	})
+	return ledger, rest
+}
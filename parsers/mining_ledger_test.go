@@ -0,0 +1,87 @@
+package parsers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var miningLedgerTestCases = []Case{
+	{
+		"Simple",
+		`2018.03.14	Pyroxeres	12,345	3,703.5 m3	1,234,567 ISK	Jita`,
+		&MiningLedger{
+			Items: []MiningLedgerItem{{Date: "2018.03.14", Name: "Pyroxeres", Quantity: 12345, Volume: 3703.5, Value: 1234567, SolarSystem: "Jita"}},
+			lines: []int{0},
+		},
+		Input{},
+		true,
+	}, {
+		"Without units or solar system",
+		`2018.03.14	Dense Veldspar	1000	100	15000.50`,
+		&MiningLedger{
+			Items: []MiningLedgerItem{{Date: "2018.03.14", Name: "Dense Veldspar", Quantity: 1000, Volume: 100, Value: 15000.5}},
+			lines: []int{0},
+		},
+		Input{},
+		true,
+	}, {
+		"Multiple days",
+		`2018.03.14	Pyroxeres	100	30 m3	10,000 ISK	Jita
+2018.03.15	Pyroxeres	200	60 m3	20,000 ISK	Jita
+2018.03.15	Scordite	50	7.5 m3	900 ISK	Jita`,
+		&MiningLedger{
+			Items: []MiningLedgerItem{
+				{Date: "2018.03.14", Name: "Pyroxeres", Quantity: 100, Volume: 30, Value: 10000, SolarSystem: "Jita"},
+				{Date: "2018.03.15", Name: "Pyroxeres", Quantity: 200, Volume: 60, Value: 20000, SolarSystem: "Jita"},
+				{Date: "2018.03.15", Name: "Scordite", Quantity: 50, Volume: 7.5, Value: 900, SolarSystem: "Jita"},
+			},
+			lines: []int{0, 1, 2},
+		},
+		Input{},
+		true,
+	},
+}
+
+var moonMiningLedgerTestCases = []Case{
+	{
+		"With corporation",
+		`2018.03.14 11:00	Some Miner	Cool Corp	Bitumens	20'000	200,000 m3	5,000,000 ISK`,
+		&MiningLedger{
+			Items: []MiningLedgerItem{{Date: "2018.03.14 11:00", PlayerName: "Some Miner", Corporation: "Cool Corp", Name: "Bitumens", Quantity: 20000, Volume: 200000, Value: 5000000}},
+			lines: []int{0},
+		},
+		Input{},
+		true,
+	}, {
+		"Without corporation",
+		`2018.03.14	Some Miner	Bitumens	20000	200000	5000000
+2018.03.14	Another Miner	Coesite	100	1000	300000`,
+		&MiningLedger{
+			Items: []MiningLedgerItem{
+				{Date: "2018.03.14", PlayerName: "Another Miner", Name: "Coesite", Quantity: 100, Volume: 1000, Value: 300000},
+				{Date: "2018.03.14", PlayerName: "Some Miner", Name: "Bitumens", Quantity: 20000, Volume: 200000, Value: 5000000},
+			},
+			lines: []int{0, 1},
+		},
+		Input{},
+		true,
+	},
+}
+
+func TestMiningLedgerTotals(t *testing.T) {
+	ledger := &MiningLedger{Items: []MiningLedgerItem{
+		{PlayerName: "Some Miner", Name: "Bitumens", Quantity: 20000, Volume: 200000, Value: 5000000},
+		{PlayerName: "Some Miner", Name: "Coesite", Quantity: 100, Volume: 1000, Value: 300000},
+		{PlayerName: "Another Miner", Name: "Bitumens", Quantity: 1000, Volume: 10000, Value: 250000},
+	}}
+
+	assert.Equal(t, []MiningLedgerTotal{
+		{Name: "Another Miner", Quantity: 1000, Volume: 10000, Value: 250000},
+		{Name: "Some Miner", Quantity: 20100, Volume: 201000, Value: 5300000},
+	}, ledger.TotalsByCharacter())
+	assert.Equal(t, []MiningLedgerTotal{
+		{Name: "Bitumens", Quantity: 21000, Volume: 210000, Value: 5250000},
+		{Name: "Coesite", Quantity: 100, Volume: 1000, Value: 300000},
+	}, ledger.TotalsByOre())
+}
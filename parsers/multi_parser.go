@@ -1,11 +1,21 @@
 package parsers
 
-import "sort"
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+)
 
-var AllParser = NewMultiParser(AllParsers)
+// AllParser runs every registered parser that can be built without deps
+func AllParser(input Input) (ParserResult, Input) {
+	stages, _ := NewParserStages(ParserDeps{}, ParserConfig{})
+	return NewStagedMultiParser(stages, nil)(input)
+}
 
 type MultiParserResult struct {
-	Results []ParserResult
+	Results   []ParserResult
+	Decisions []LineDecision
 }
 
 func (r *MultiParserResult) Name() string {
@@ -21,21 +31,314 @@ func (r *MultiParserResult) Lines() []int {
 	return lines
 }
 
+// LineDecision records which parser took a line of the input and why. Parser is empty for lines that no parser
+// could use.
+type LineDecision struct {
+	Line       int             `json:"line"`
+	Text       string          `json:"text"`
+	Parser     string          `json:"parser,omitempty"`
+	Confidence float64         `json:"confidence"`
+	Reason     string          `json:"reason"`
+	Candidates []LineCandidate `json:"candidates,omitempty"`
+}
+
+// LineCandidate is a parser that wanted a line, with how sure it was about it
+type LineCandidate struct {
+	Parser     string  `json:"parser"`
+	Confidence float64 `json:"confidence"`
+	Reason     string  `json:"reason"`
+}
+
 func NewMultiParser(parsers []Parser) Parser {
+	return NewFilteredMultiParser(parsers, nil)
+}
+
+// ParserStage is a group of parsers that are never more sure about a line than MaxConfidence. Lines that an
+// earlier parser already claimed with at least that confidence can't go to them, so they don't get to see them.
+type ParserStage struct {
+	Parsers       []Parser
+	MaxConfidence float64
+}
+
+// NewFilteredMultiParser returns a parser that gives every line to the parser that is most confident about it.
+// Each line goes to the parser with the highest confidence for it; ties go to the parser that comes first. Lines
+// that end up unclaimed are contested again by the parsers that wanted them, for at most maxClaimRounds rounds.
+// Results that accept rejects are ignored, accept may be nil.
+func NewFilteredMultiParser(parsers []Parser, accept func(ParserResult) bool) Parser {
+	return NewStagedMultiParser([]ParserStage{{Parsers: parsers, MaxConfidence: 1}}, accept)
+}
+
+// NewStagedMultiParser works like NewFilteredMultiParser with the parsers of all stages in order, except that
+// parsers only look at the lines that the parsers before them weren't sure enough about to rule them out. The
+// sure formats can then go first on the whole input and the loose formats only see what is left.
+func NewStagedMultiParser(stages []ParserStage, accept func(ParserResult) bool) Parser {
+	var (
+		parsers       []Parser
+		maxConfidence []float64
+	)
+	for _, stage := range stages {
+		for _, parser := range stage.Parsers {
+			parsers = append(parsers, parser)
+			maxConfidence = append(maxConfidence, stage.MaxConfidence)
+		}
+	}
+
 	return Parser(
 		func(input Input) (ParserResult, Input) {
 			multiParserResult := &MultiParserResult{}
-			left := input
-			for _, parser := range parsers {
-				if len(left) == 0 {
+			decisions := make(map[int]LineDecision)
+			left := make(Input)
+			contested := input
+			active := make([]int, len(parsers))
+			for i := range parsers {
+				active[i] = i
+			}
+			for round := 0; len(contested) > 0; round++ {
+				if round == maxClaimRounds {
+					for line, text := range contested {
+						left[line] = text
+					}
 					break
 				}
-				var result ParserResult
-				result, left = parser(left)
-				if result != nil && len(result.Lines()) > 0 {
-					multiParserResult.Results = append(multiParserResult.Results, result)
+
+				var (
+					claimed  bool
+					unwanted Input
+				)
+				claimed, contested, unwanted, active = claimLines(parsers, maxConfidence, active, accept, contested, multiParserResult, decisions)
+				for line, text := range unwanted {
+					left[line] = text
 				}
+				if !claimed {
+					for line, text := range contested {
+						left[line] = text
+					}
+					break
+				}
+			}
+
+			for _, line := range left.LineNumbers() {
+				decision, ok := decisions[line]
+				if !ok && strings.TrimSpace(left[line]) == "" {
+					continue
+				}
+				decision.Line = line
+				decision.Text = left[line]
+				decision.Parser = ""
+				decision.Confidence = 0
+				decision.Reason = "no parser could use this line"
+				decisions[line] = decision
 			}
+
+			for _, decision := range decisions {
+				multiParserResult.Decisions = append(multiParserResult.Decisions, decision)
+			}
+			sort.Slice(multiParserResult.Decisions, func(i, j int) bool {
+				return multiParserResult.Decisions[i].Line < multiParserResult.Decisions[j].Line
+			})
 			return multiParserResult, left
 		})
 }
+
+// maxClaimRounds limits how many times contested lines are parsed again
+const maxClaimRounds = 3
+
+type lineCandidate struct {
+	parser     int
+	name       string
+	confidence float64
+	reason     string
+	rejected   bool
+}
+
+// claimLines runs one round of parsing with the active parsers, each on the lines it could still win. Lines a
+// parser leaves out of its rest count as its lines, even if they didn't yield anything (like headers). Parsers that
+// lose some of their lines to a more confident parser are run again on just the lines they won, which can leave
+// lines that were wanted unclaimed.
+// Only those contested lines and the parsers that wanted them take part in the next round, lines that no parser
+// wanted are returned as unwanted. It returns false if no lines were claimed.
+func claimLines(parsers []Parser, maxConfidence []float64, active []int, accept func(ParserResult) bool, input Input, multiParserResult *MultiParserResult, decisions map[int]LineDecision) (bool, Input, Input, []int) {
+	results := make([]ParserResult, len(parsers))
+	consumed := make([][]int, len(parsers))
+	candidates := make(map[int][]lineCandidate)
+	var (
+		open    Input
+		ceiling float64
+		best    float64
+		stale   = true
+	)
+	for _, i := range active {
+		if maxConfidence[i] != ceiling {
+			ceiling = maxConfidence[i]
+			stale = true
+		}
+		if stale {
+			open = input
+			if best >= ceiling {
+				open = openLines(input, candidates, ceiling)
+			}
+			stale = false
+		}
+		if len(open) == 0 {
+			continue
+		}
+
+		result, rest := parsers[i](open)
+		if result == nil || len(result.Lines()) == 0 {
+			continue
+		}
+		consumed[i] = consumedLines(open, rest)
+
+		if accept != nil && !accept(result) {
+			for _, line := range consumed[i] {
+				candidates[line] = append(candidates[line], lineCandidate{parser: i, name: result.Name(), reason: "none of its items are known types", rejected: true})
+				decisions[line] = newLineDecision(line, input[line], candidates[line])
+			}
+			continue
+		}
+
+		results[i] = result
+		for _, line := range consumed[i] {
+			confidence, reason := LineConfidence(result, input[line])
+			candidates[line] = append(candidates[line], lineCandidate{parser: i, name: result.Name(), confidence: confidence, reason: reason})
+			best = math.Max(best, confidence)
+			stale = stale || confidence >= ceiling
+		}
+	}
+
+	won := make([]Input, len(parsers))
+	for line, lineCandidates := range candidates {
+		best := -1
+		for j, c := range lineCandidates {
+			if c.rejected {
+				continue
+			}
+			if best == -1 || c.confidence > lineCandidates[best].confidence ||
+				(c.confidence == lineCandidates[best].confidence && c.parser < lineCandidates[best].parser) {
+				best = j
+			}
+		}
+		if best == -1 {
+			continue
+		}
+
+		winner := lineCandidates[best].parser
+		if won[winner] == nil {
+			won[winner] = make(Input)
+		}
+		won[winner][line] = input[line]
+		decisions[line] = newLineDecision(line, input[line], lineCandidates)
+	}
+
+	claimed := make(map[int]bool)
+	for i, result := range results {
+		if len(won[i]) == 0 {
+			continue
+		}
+
+		lines := consumed[i]
+		if len(won[i]) != len(lines) {
+			var rest Input
+			result, rest = parsers[i](won[i])
+			if result == nil || len(result.Lines()) == 0 || (accept != nil && !accept(result)) {
+				continue
+			}
+			lines = consumedLines(won[i], rest)
+		}
+
+		multiParserResult.Results = append(multiParserResult.Results, result)
+		for _, line := range lines {
+			claimed[line] = true
+		}
+	}
+
+	contested := make(Input)
+	unwanted := make(Input)
+	wanting := make(map[int]bool)
+	for line, text := range input {
+		if claimed[line] {
+			continue
+		}
+
+		wanted := false
+		for _, c := range candidates[line] {
+			if !c.rejected {
+				wanted = true
+				wanting[c.parser] = true
+			}
+		}
+		if wanted {
+			contested[line] = text
+		} else {
+			unwanted[line] = text
+		}
+	}
+
+	var next []int
+	for _, i := range active {
+		if wanting[i] {
+			next = append(next, i)
+		}
+	}
+	return len(claimed) > 0, contested, unwanted, next
+}
+
+// openLines returns the lines of the input that a parser that is at most ceiling sure about its lines could still
+// win. Ties go to the parser that comes first, so lines that were claimed with the ceiling are already lost.
+func openLines(input Input, candidates map[int][]lineCandidate, ceiling float64) Input {
+	open := make(Input)
+	for line, text := range input {
+		lost := false
+		for _, c := range candidates[line] {
+			lost = lost || (!c.rejected && c.confidence >= ceiling)
+		}
+		if !lost {
+			open[line] = text
+		}
+	}
+	return open
+}
+
+// consumedLines returns the lines of the input that a parser didn't give back
+func consumedLines(input Input, rest Input) []int {
+	var lines []int
+	for line := range input {
+		if _, ok := rest[line]; !ok {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
+// newLineDecision describes why the most confident candidate won the line
+func newLineDecision(line int, text string, lineCandidates []lineCandidate) LineDecision {
+	decision := LineDecision{Line: line, Text: text}
+	sorted := make([]lineCandidate, len(lineCandidates))
+	copy(sorted, lineCandidates)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].confidence != sorted[j].confidence {
+			return sorted[i].confidence > sorted[j].confidence
+		}
+		return sorted[i].parser < sorted[j].parser
+	})
+
+	var others []string
+	for _, c := range sorted {
+		decision.Candidates = append(decision.Candidates, LineCandidate{Parser: c.name, Confidence: c.confidence, Reason: c.reason})
+		if c.rejected {
+			continue
+		}
+		if decision.Parser == "" {
+			decision.Parser = c.name
+			decision.Confidence = c.confidence
+			decision.Reason = c.reason
+		} else {
+			others = append(others, fmt.Sprintf("%s (%.2f)", c.name, c.confidence))
+		}
+	}
+
+	if len(others) > 0 {
+		decision.Reason += "; preferred over " + strings.Join(others, ", ")
+	}
+	return decision
+}
@@ -0,0 +1,269 @@
+package parsers
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type scoredResult struct {
+	name       string
+	confidence float64
+	lines      []int
+}
+
+func (r *scoredResult) Name() string {
+	return r.name
+}
+
+func (r *scoredResult) Lines() []int {
+	return r.lines
+}
+
+func (r *scoredResult) LineConfidence(text string) (float64, string) {
+	return r.confidence, r.name + " line"
+}
+
+// newScoredParser returns a parser that claims every line containing one of the given words
+func newScoredParser(name string, confidence float64, words ...string) Parser {
+	return func(input Input) (ParserResult, Input) {
+		result := &scoredResult{name: name, confidence: confidence}
+		rest := make(Input)
+		for _, line := range input.LineNumbers() {
+			matched := false
+			for _, word := range words {
+				if strings.Contains(input[line], word) {
+					matched = true
+				}
+			}
+			if matched {
+				result.lines = append(result.lines, line)
+			} else {
+				rest[line] = input[line]
+			}
+		}
+		return result, rest
+	}
+}
+
+func TestMultiParserConfidence(t *testing.T) {
+	p := NewMultiParser([]Parser{
+		newScoredParser("loose", 0.4, "Tritanium", "Pyerite", "Mexallon"),
+		newScoredParser("strict", 0.9, "Pyerite"),
+	})
+
+	result, rest := p(StringToInput("Tritanium\nPyerite\nMexallon\nIsogen"))
+	assert.Equal(t, []ParserResult{
+		&scoredResult{name: "loose", confidence: 0.4, lines: []int{0, 2}},
+		&scoredResult{name: "strict", confidence: 0.9, lines: []int{1}},
+	}, result.(*MultiParserResult).Results)
+	assert.Equal(t, Input{3: "Isogen"}, rest)
+
+	assert.Equal(t, []LineDecision{
+		{Line: 0, Text: "Tritanium", Parser: "loose", Confidence: 0.4, Reason: "loose line",
+			Candidates: []LineCandidate{{Parser: "loose", Confidence: 0.4, Reason: "loose line"}}},
+		{Line: 1, Text: "Pyerite", Parser: "strict", Confidence: 0.9, Reason: "strict line; preferred over loose (0.40)",
+			Candidates: []LineCandidate{
+				{Parser: "strict", Confidence: 0.9, Reason: "strict line"},
+				{Parser: "loose", Confidence: 0.4, Reason: "loose line"},
+			}},
+		{Line: 2, Text: "Mexallon", Parser: "loose", Confidence: 0.4, Reason: "loose line",
+			Candidates: []LineCandidate{{Parser: "loose", Confidence: 0.4, Reason: "loose line"}}},
+		{Line: 3, Text: "Isogen", Reason: "no parser could use this line"},
+	}, result.(*MultiParserResult).Decisions)
+}
+
+func TestMultiParserConfidenceTie(t *testing.T) {
+	p := NewMultiParser([]Parser{
+		newScoredParser("first", 0.5, "Tritanium"),
+		newScoredParser("second", 0.5, "Tritanium"),
+	})
+
+	result, _ := p(StringToInput("Tritanium"))
+	assert.Equal(t, []ParserResult{
+		&scoredResult{name: "first", confidence: 0.5, lines: []int{0}},
+	}, result.(*MultiParserResult).Results)
+}
+
+func TestMultiParserRejected(t *testing.T) {
+	p := NewFilteredMultiParser([]Parser{
+		newScoredParser("rejected", 0.9, "Tritanium"),
+		newScoredParser("accepted", 0.5, "Tritanium"),
+	}, func(result ParserResult) bool {
+		return result.Name() != "rejected"
+	})
+
+	result, rest := p(StringToInput("Tritanium"))
+	assert.Equal(t, []ParserResult{
+		&scoredResult{name: "accepted", confidence: 0.5, lines: []int{0}},
+	}, result.(*MultiParserResult).Results)
+	assert.Equal(t, Input{}, rest)
+	assert.Equal(t, "accepted", result.(*MultiParserResult).Decisions[0].Parser)
+}
+
+func TestAssetListLineConfidence(t *testing.T) {
+	assets := &AssetList{}
+	full, _ := assets.LineConfidence("Hammerhead II\t5\tMedium Scout Drone\tDrone\t\t\t50 m3")
+	nameOnly, _ := assets.LineConfidence("Hammerhead II\t5")
+	number, _ := assets.LineConfidence("123\t")
+	assert.True(t, full > defaultConfidence["listing"])
+	assert.Equal(t, defaultConfidence["listing"], nameOnly)
+	assert.True(t, number < defaultConfidence["heuristic"])
+}
+
+// recordingParser remembers every input it was run on
+func recordingParser(parser Parser, inputs *[]Input) Parser {
+	return func(input Input) (ParserResult, Input) {
+		*inputs = append(*inputs, input)
+		return parser(input)
+	}
+}
+
+func TestMultiParserContestedRounds(t *testing.T) {
+	// pairs only takes Tritanium and Pyerite together, so after losing Pyerite its re-parse leaves Tritanium
+	pairs := func(input Input) (ParserResult, Input) {
+		result := &scoredResult{name: "pairs", confidence: 0.4}
+		rest := make(Input)
+		hasPyerite := false
+		for _, text := range input {
+			hasPyerite = hasPyerite || text == "Pyerite"
+		}
+		for line, text := range input {
+			if hasPyerite && (text == "Tritanium" || text == "Pyerite") {
+				result.lines = append(result.lines, line)
+			} else {
+				rest[line] = text
+			}
+		}
+		return result, rest
+	}
+
+	var pairsInputs, strictInputs []Input
+	p := NewMultiParser([]Parser{
+		recordingParser(pairs, &pairsInputs),
+		recordingParser(newScoredParser("strict", 0.9, "Pyerite"), &strictInputs),
+	})
+
+	result, rest := p(StringToInput("Tritanium\nPyerite\nIsogen"))
+	assert.Equal(t, []ParserResult{
+		&scoredResult{name: "strict", confidence: 0.9, lines: []int{1}},
+	}, result.(*MultiParserResult).Results)
+	assert.Equal(t, Input{0: "Tritanium", 2: "Isogen"}, rest)
+
+	// The second round only has the contested line and only the parser that wanted it
+	assert.Len(t, strictInputs, 1)
+	assert.Equal(t, []Input{
+		{0: "Tritanium", 1: "Pyerite", 2: "Isogen"},
+		{0: "Tritanium"},
+		{0: "Tritanium"},
+	}, pairsInputs)
+	assert.Equal(t, "no parser could use this line", result.(*MultiParserResult).Decisions[0].Reason)
+	assert.Equal(t, "no parser could use this line", result.(*MultiParserResult).Decisions[2].Reason)
+}
+
+func TestMultiParserStages(t *testing.T) {
+	var looseInputs []Input
+	p := NewStagedMultiParser([]ParserStage{
+		{Parsers: []Parser{newScoredParser("sure", 0.9, "Tritanium"), newScoredParser("unsure", 0.5, "Pyerite")}, MaxConfidence: 1},
+		{Parsers: []Parser{recordingParser(newScoredParser("loose", 0.6, "Tritanium", "Pyerite", "Mexallon"), &looseInputs)}, MaxConfidence: 0.6},
+	}, nil)
+
+	result, rest := p(StringToInput("Tritanium\nPyerite\nMexallon\nIsogen"))
+	assert.Equal(t, []ParserResult{
+		&scoredResult{name: "sure", confidence: 0.9, lines: []int{0}},
+		&scoredResult{name: "loose", confidence: 0.6, lines: []int{1, 2}},
+	}, result.(*MultiParserResult).Results)
+	assert.Equal(t, Input{3: "Isogen"}, rest)
+
+	// The loose parser couldn't have won the line that was claimed with more than it is ever sure about
+	assert.Equal(t, []Input{{1: "Pyerite", 2: "Mexallon", 3: "Isogen"}}, looseInputs)
+}
+
+// firstLineResult is very sure about the first of its two lines and not sure about the second
+type firstLineResult struct {
+	scoredResult
+	first string
+}
+
+func (r *firstLineResult) LineConfidence(text string) (float64, string) {
+	if text == r.first {
+		return 0.9, "first line"
+	}
+	return 0.3, "second line"
+}
+
+func TestMultiParserMaxRounds(t *testing.T) {
+	// firstTwo wins the first line of every round and wants the second one. all takes every line, but only
+	// when it isn't given the lines it won again, so each round leaves the rest of the lines contested.
+	firstTwo := func(input Input) (ParserResult, Input) {
+		lines := input.LineNumbers()
+		if len(lines) > 2 {
+			lines = lines[:2]
+		}
+		result := &firstLineResult{scoredResult: scoredResult{name: "first_two", lines: lines}, first: input[lines[0]]}
+		rest := make(Input)
+		for line, text := range input {
+			rest[line] = text
+		}
+		for _, line := range lines {
+			delete(rest, line)
+		}
+		return result, rest
+	}
+	calls := 0
+	all := func(input Input) (ParserResult, Input) {
+		calls++
+		if calls%2 == 0 {
+			return nil, input
+		}
+		return &scoredResult{name: "all", confidence: 0.4, lines: input.LineNumbers()}, Input{}
+	}
+
+	var lines []string
+	for i := 0; i < 10; i++ {
+		lines = append(lines, fmt.Sprintf("line %d", i))
+	}
+	result, rest := NewMultiParser([]Parser{firstTwo, all})(StringToInput(strings.Join(lines, "\n")))
+	assert.Len(t, result.(*MultiParserResult).Results, maxClaimRounds)
+	assert.Len(t, rest, 10-maxClaimRounds)
+	assert.Equal(t, "no parser could use this line", result.(*MultiParserResult).Decisions[maxClaimRounds].Reason)
+}
+
+func BenchmarkAllParser(b *testing.B) {
+	var lines []string
+	for i := 0; i < 1000; i++ {
+		lines = append(lines,
+			fmt.Sprintf("Tritanium %d", i+1),
+			fmt.Sprintf("Hammerhead II\t%d\tMedium Scout Drone\tDrone\t\t\t50 m3", i+1),
+			fmt.Sprintf("%d x Pyerite", i+1),
+			"not an item")
+	}
+	input := StringToInput(strings.Join(lines, "\n"))
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		AllParser(input)
+	}
+}
+
+func TestLineConfidence(t *testing.T) {
+	cases := []struct {
+		result ParserResult
+		better string
+		worse  string
+	}{
+		{&Listing{}, "5 Tritanium", "Tritanium"},
+		{&Multibuy{}, "Tritanium 5", "5 Tritanium 10"},
+		{&Contract{}, "Rifter\t1\tFrigate\tShip\t", "Rifter\t1\tFrigate"},
+		{&Contract{}, "Rifter\t1\tFrigate", "123\t1\tFrigate"},
+		{&Industry{}, "Tritanium\t100\t50\t50", "Rifter Blueprint\t3"},
+		{&HeuristicResult{}, "123\t Drone Transceiver", "some Drone Transceiver here"},
+	}
+	for _, c := range cases {
+		better, _ := LineConfidence(c.result, c.better)
+		worse, _ := LineConfidence(c.result, c.worse)
+		assert.True(t, better > worse, "%s: %q (%.2f) should beat %q (%.2f)", c.result.Name(), c.better, better, c.worse, worse)
+	}
+}
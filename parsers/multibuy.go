@@ -0,0 +1,86 @@
+package parsers
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+type Multibuy struct {
+	Items []ListingItem
+	lines []int
+
+	// exported is set when the paste has the price columns or the total line that only multibuy exports have
+	exported bool
+}
+
+func (r *Multibuy) Name() string {
+	return "multibuy"
+}
+
+func (r *Multibuy) Lines() []int {
+	return r.lines
+}
+
+var reMultibuy = regexp.MustCompile(strings.Join([]string{
+	`^([\S ]*[^\d\s,'\.][\S ]*?)`, // name
+	`[ \t]([\d,'\.]+)`,            // quantity
+	`((?:\t[\d,'\.]+ ISK)*)$`,     // price and total columns of an export
+}, ""))
+
+// reMultibuyTotal is the last line of a multibuy export
+var reMultibuyTotal = regexp.MustCompile(`^(?i)total:?\t+[\d,'\.]+(?: ISK)?$`)
+
+// LineConfidence is lower than for a listing unless the paste was exported from the multibuy window, since plain
+// "Name quantity" lines are a listing just as much. It is lower still when the name starts with a number, since
+// "5 Tritanium 10" is more likely a listing with a number in the name than a multibuy line.
+func (r *Multibuy) LineConfidence(text string) (float64, string) {
+	match := reMultibuy.FindStringSubmatch(text)
+	if match != nil && reLeadingNumber.MatchString(match[1]) {
+		return 0.5, "the name starts with a number"
+	}
+	if r.exported {
+		return 0.8, "the paste has the price columns or the total of a multibuy export"
+	}
+	return defaultConfidence["multibuy"], "every line of the paste is a name and a quantity"
+}
+
+var reLeadingNumber = regexp.MustCompile(`^[\d,'\.]+ `)
+
+func init() {
+	RegisterParser("multibuy", 170, ParseMultibuy)
+}
+
+// ParseMultibuy parses the "Name quantity" lists that the multibuy window imports and exports, along with the
+// prices and the total line of an export. Since lines like that show up in lots of pastes, the input is only
+// taken if every line in it looks like that.
+func ParseMultibuy(input Input) (ParserResult, Input) {
+	multibuy := &Multibuy{}
+	matches, rest := regexParseLines(reMultibuy, input)
+	totals, rest := regexParseLines(reMultibuyTotal, rest)
+	for _, text := range rest {
+		if strings.TrimSpace(text) != "" {
+			return multibuy, input
+		}
+	}
+	multibuy.lines = append(regexMatchedLines(matches), regexMatchedLines(totals)...)
+	sort.Ints(multibuy.lines)
+	multibuy.exported = len(totals) > 0
+
+	matchgroup := make(map[ListingItem]int64)
+	for _, match := range matches {
+		matchgroup[ListingItem{Name: CleanTypeName(match[1])}] += ToInt(match[2])
+		multibuy.exported = multibuy.exported || match[3] != ""
+	}
+
+	for item, quantity := range matchgroup {
+		item.Quantity = quantity
+		multibuy.Items = append(multibuy.Items, item)
+	}
+
+	sort.Slice(multibuy.Items, func(i, j int) bool {
+		return fmt.Sprintf("%v", multibuy.Items[i]) < fmt.Sprintf("%v", multibuy.Items[j])
+	})
+	return multibuy, rest
+}
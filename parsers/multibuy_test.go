@@ -0,0 +1,58 @@
+package parsers
+
+var multibuyTestCases = []Case{
+	{
+		"Basic",
+		`Tritanium 10000
+Heavy Assault Missile Launcher II 10
+Tritanium 5,000
+Compressed Arkonor 1'000`,
+		&Multibuy{
+			Items: []ListingItem{
+				{Name: "Compressed Arkonor", Quantity: 1000},
+				{Name: "Heavy Assault Missile Launcher II", Quantity: 10},
+				{Name: "Tritanium", Quantity: 15000},
+			},
+			lines: []int{0, 1, 2, 3},
+		},
+		Input{},
+		false, // This is also the listing format
+	}, {
+		"Export",
+		`Tritanium	10000	5.01 ISK	50,100.00 ISK
+Heavy Assault Missile Launcher II	10	1,100,000.00 ISK	11,000,000.00 ISK
+Total:			11,050,100.00 ISK`,
+		&Multibuy{
+			Items: []ListingItem{
+				{Name: "Heavy Assault Missile Launcher II", Quantity: 10},
+				{Name: "Tritanium", Quantity: 10000},
+			},
+			lines:    []int{0, 1, 2},
+			exported: true,
+		},
+		Input{},
+		true,
+	}, {
+		"Total line",
+		`Tritanium 10000
+Pyerite 500
+Total:	53,100.00 ISK`,
+		&Multibuy{
+			Items: []ListingItem{
+				{Name: "Pyerite", Quantity: 500},
+				{Name: "Tritanium", Quantity: 10000},
+			},
+			lines:    []int{0, 1, 2},
+			exported: true,
+		},
+		Input{},
+		true,
+	}, {
+		"Mixed with other formats",
+		`Tritanium 10000
+10x Minmatar Shuttle`,
+		&Multibuy{},
+		Input{0: "Tritanium 10000", 1: "10x Minmatar Shuttle"},
+		false,
+	},
+}
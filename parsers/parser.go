@@ -7,20 +7,3 @@ type ParserResult interface {
 	Name() string
 	Lines() []int
 }
-
-var AllParsers = []Parser{
-	ParseKillmail,
-	ParseEFT,
-	ParseFitting,
-	ParseLootHistory,
-	ParsePI,
-	ParseViewContents,
-	ParseWallet,
-	ParseSurveyScan,
-	ParseContract,
-	ParseAssets,
-	ParseIndustry,
-	ParseCargoScan,
-	ParseDScan,
-	ParseListing,
-}
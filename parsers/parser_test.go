@@ -1,8 +1,10 @@
 package parsers
 
 import (
+	"sort"
 	"testing"
 
+	"github.com/evepraisal/go-evepraisal/typedb"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -26,10 +28,16 @@ var ParserTests = []CaseGroup{
 	{"contracts", ParseContract, contractTestCases},
 	{"dscan", ParseDScan, dscanTestCases},
 	{"listing", ParseListing, listingTestCases},
+	{"multibuy", ParseMultibuy, multibuyTestCases},
+	{"quickbar", ParseQuickbar, quickbarTestCases},
 	{"eft", ParseEFT, eftTestCases},
 	{"fitting", ParseFitting, fittingTestCases},
+	{"xml_fitting", ParseXMLFitting, xmlFittingTestCases},
 	{"industry", ParseIndustry, industryTestCases},
 	{"loot_history", ParseLootHistory, lootHistoryTestCases},
+	{"mining_ledger", ParseMiningLedger, miningLedgerTestCases},
+	{"moon_mining_ledger", ParseMoonMiningLedger, moonMiningLedgerTestCases},
+	{"market_orders", ParseMarketOrders, marketOrdersTestCases},
 	{"pi", ParsePI, piTestCases},
 	{"survey_scanner", ParseSurveyScan, surveyScannerTestCases},
 	{"view_contents", ParseViewContents, viewContentsTestCases},
@@ -57,13 +65,44 @@ func TestParsers(rt *testing.T) {
 			rt.Run("AllParser_"+group.name+":"+c.Description, func(t *testing.T) {
 				result, rest := AllParser(StringToInput(c.Input))
 
-				expectedResult := &MultiParserResult{Results: []ParserResult{c.Expected}}
+				expectedResult := []ParserResult{c.Expected}
 				if c.Expected == nil {
-					expectedResult = &MultiParserResult{Results: nil}
+					expectedResult = nil
 				}
-				assert.Equal(t, expectedResult, result, "results should be the same")
-				assert.Equal(t, c.ExpectedRest, rest, "the rest should be the same")
+				expectedRest := c.ExpectedRest
+				if expectedRest == nil {
+					expectedRest = Input{}
+				}
+				assert.Equal(t, expectedResult, result.(*MultiParserResult).Results, "results should be the same")
+				assert.Equal(t, expectedRest, rest, "the rest should be the same")
 			})
 		}
 	}
 }
+
+// A quantity, a tab and a name with a leading space (from todo.txt) isn't any known format, so it's up to the
+// heuristic parser
+func TestQuantityTabName(t *testing.T) {
+	db := &StaticTypeDB{typeNameMap: make(map[string]typedb.EveType), typeIDMap: make(map[int64]typedb.EveType)}
+	db.PutType(typedb.EveType{Name: "Drone Transceiver"})
+	db.PutType(typedb.EveType{Name: "Logic Circuit"})
+	parsers, err := NewParsers(ParserDeps{TypeDB: db}, ParserConfig{})
+	assert.NoError(t, err)
+
+	result, rest := NewMultiParser(parsers)(StringToInput("123\t Drone Transceiver\n145\t Logic Circuit"))
+	assert.Equal(t, Input{}, rest)
+	results := result.(*MultiParserResult).Results
+	assert.Len(t, results, 1)
+	heuristic, ok := results[0].(*HeuristicResult)
+	assert.True(t, ok, "expected a heuristic result, got %T", results[0])
+	if ok {
+		sort.Slice(heuristic.Items, func(i, j int) bool { return heuristic.Items[i].Name < heuristic.Items[j].Name })
+		sort.Ints(heuristic.lines)
+		assert.Equal(t, []HeuristicItem{{Name: "Drone Transceiver", Quantity: 123}, {Name: "Logic Circuit", Quantity: 145}}, heuristic.Items)
+		assert.Equal(t, []int{0, 1}, heuristic.lines)
+	}
+	for _, decision := range result.(*MultiParserResult).Decisions {
+		assert.Equal(t, "heuristic", decision.Parser)
+		assert.Equal(t, 0.5, decision.Confidence)
+	}
+}
@@ -28,9 +28,9 @@ type PIItem struct {
 }
 
 var rePI1 = regexp.MustCompile(strings.Join([]string{
-	`^([\d,'\.]+)\t`,          // quantity
-	`([\S ]+)\t`,              // name
-	`((Routed|Not\ routed))$`, // routed
+	`^([\d,'\.]+)\t`, // quantity
+	`([\S ]+)\t`,     // name
+	`(` + keywordPattern(localizedRouted, localizedNotRouted) + `)$`, // routed
 }, ""))
 
 var rePI2 = regexp.MustCompile(strings.Join([]string{
@@ -46,6 +46,10 @@ var rePI3 = regexp.MustCompile(strings.Join([]string{
 	`([\d,'\.]+)$`, // quantity
 }, ""))
 
+func init() {
+	RegisterParser("pi", 120, ParsePI)
+}
+
 func ParsePI(input Input) (ParserResult, Input) {
 	pi := &PI{}
 	matches1, rest := regexParseLines(rePI1, input)
@@ -58,19 +62,19 @@ func ParsePI(input Input) (ParserResult, Input) {
 	// collect items
 	matchgroup := make(map[PIItem]int64)
 	for _, match := range matches1 {
-		item := PIItem{Name: match[2], Routed: match[3] == "Routed"}
+		item := PIItem{Name: match[2], Routed: isKeyword(localizedRouted, match[3])}
 
-		matchgroup[item] += int64(ToFloat64(match[1]))
+		matchgroup[item] += int64(ToDecimal(match[1]))
 	}
 
 	for _, match := range matches2 {
-		item := PIItem{Name: match[1], Volume: ToFloat64(match[3])}
-		matchgroup[item] += int64(ToFloat64(match[2]))
+		item := PIItem{Name: match[1], Volume: ToDecimal(match[3])}
+		matchgroup[item] += int64(ToDecimal(match[2]))
 	}
 
 	for _, match := range matches3 {
 		item := PIItem{Name: match[1]}
-		matchgroup[item] += int64(ToFloat64(match[2]))
+		matchgroup[item] += int64(ToDecimal(match[2]))
 	}
 
 	// add items w/totals
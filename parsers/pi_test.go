@@ -36,6 +36,18 @@ var piTestCases = []Case{
 		},
 		Input{},
 		true,
+	}, {
+		"German client",
+		"2.000.000\tMikroorganismen\tGeroutet\n20\tBakterien\tNicht geroutet",
+		&PI{
+			Items: []PIItem{
+				{Name: "Bakterien", Quantity: 20, Routed: false},
+				{Name: "Mikroorganismen", Quantity: 2000000, Routed: true},
+			},
+			lines: []int{0, 1},
+		},
+		Input{},
+		false,
 	},
 }
 
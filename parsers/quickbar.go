@@ -0,0 +1,95 @@
+package parsers
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+type Quickbar struct {
+	Items []QuickbarItem
+	lines []int
+}
+
+func (r *Quickbar) Name() string {
+	return "quickbar"
+}
+
+func (r *Quickbar) Lines() []int {
+	return r.lines
+}
+
+// QuickbarItem is an item from a market quickbar. Folder is the path of folders it is in, separated by
+// ContainerPathSeparator.
+type QuickbarItem struct {
+	Name     string
+	Quantity int64
+	Folder   string
+}
+
+var reQuickbarFolder = regexp.MustCompile(strings.Join([]string{
+	`^\s*([+ ]*\+)`, // depth
+	`\s*([\S ]+)$`,  // folder name
+}, ""))
+
+var reQuickbarItem = regexp.MustCompile(strings.Join([]string{
+	`^\s*[- ]*-\s*`,               // marker
+	`([\S ]+?)`,                   // name
+	`(?:\s*\[([\d,'\.]+)\])?\s*$`, // quantity
+}, ""))
+
+func init() {
+	RegisterParser("quickbar", 50, ParseQuickbar)
+}
+
+// ParseQuickbar parses market quickbar exports, which have "+ Folder" lines (with one more "+" for every level
+// of nesting) followed by "- Item" or "- Item [quantity]" lines.
+func ParseQuickbar(input Input) (ParserResult, Input) {
+	quickbar := &Quickbar{}
+	rest := make(Input)
+
+	var folders []string
+	matchgroup := make(map[QuickbarItem]int64)
+	for _, line := range input.LineNumbers() {
+		text := input[line]
+		if match := reQuickbarFolder.FindStringSubmatch(text); match != nil {
+			depth := strings.Count(match[1], "+")
+			if depth > len(folders)+1 {
+				depth = len(folders) + 1
+			}
+			folders = append(folders[:depth-1], strings.TrimSpace(match[2]))
+			quickbar.lines = append(quickbar.lines, line)
+			continue
+		}
+
+		match := reQuickbarItem.FindStringSubmatch(text)
+		if match == nil || len(folders) == 0 {
+			rest[line] = text
+			continue
+		}
+
+		quantity := ToInt(match[2])
+		if quantity == 0 {
+			quantity = 1
+		}
+		item := QuickbarItem{Name: CleanTypeName(match[1]), Folder: strings.Join(folders, ContainerPathSeparator)}
+		matchgroup[item] += quantity
+		quickbar.lines = append(quickbar.lines, line)
+	}
+
+	// Folders alone don't make a quickbar
+	if len(matchgroup) == 0 {
+		return &Quickbar{}, input
+	}
+
+	for item, quantity := range matchgroup {
+		item.Quantity = quantity
+		quickbar.Items = append(quickbar.Items, item)
+	}
+
+	sort.Slice(quickbar.Items, func(i, j int) bool {
+		return fmt.Sprintf("%v", quickbar.Items[i]) < fmt.Sprintf("%v", quickbar.Items[j])
+	})
+	return quickbar, rest
+}
@@ -0,0 +1,34 @@
+package parsers
+
+var quickbarTestCases = []Case{
+	{
+		"Folders",
+		`+ Minerals
+- Tritanium [10,000]
+- Pyerite
++ Ships
+++ Frigates
+- Rifter [2]
+- Rifter
++ Ammo
+- - Republic Fleet EMP S [1000]`,
+		&Quickbar{
+			Items: []QuickbarItem{
+				{Name: "Pyerite", Quantity: 1, Folder: "Minerals"},
+				{Name: "Republic Fleet EMP S", Quantity: 1000, Folder: "Ammo"},
+				{Name: "Rifter", Quantity: 3, Folder: "Ships > Frigates"},
+				{Name: "Tritanium", Quantity: 10000, Folder: "Minerals"},
+			},
+			lines: []int{0, 1, 2, 3, 4, 5, 6, 7, 8},
+		},
+		Input{},
+		true,
+	}, {
+		"Items without a folder",
+		`- Tritanium [5]
+- Pyerite`,
+		&Quickbar{},
+		Input{0: "- Tritanium [5]", 1: "- Pyerite"},
+		false,
+	},
+}
@@ -0,0 +1,150 @@
+package parsers
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/evepraisal/go-evepraisal/typedb"
+)
+
+// ParserDeps is what registered parsers are built with. Both can be nil; parsers that need the type database
+// aren't built without one.
+type ParserDeps struct {
+	TypeDB        typedb.TypeDB
+	FetchKillmail KillmailFetchFunc
+}
+
+// ParserFactory builds a parser with the deps. It returns nil if the parser can't be built with them.
+type ParserFactory func(deps ParserDeps) Parser
+
+// Registration is a parser in the registry. Parsers with a lower priority come first, which means they win lines
+// that parsers with a higher priority are just as confident about.
+type Registration struct {
+	Name     string
+	Priority int
+	New      ParserFactory
+}
+
+// ParserConfig changes the registered parsers for a deployment. If Enabled isn't empty only the parsers in it are
+// used. Disabled parsers are never used. Priorities overrides the priority of parsers by name.
+type ParserConfig struct {
+	Enabled    []string
+	Disabled   []string
+	Priorities map[string]int
+}
+
+var registry = make(map[string]Registration)
+
+// Register adds a parser to the registry. It panics if a parser with the name is already registered.
+func Register(name string, priority int, factory ParserFactory) {
+	if _, ok := registry[name]; ok {
+		panic(fmt.Sprintf("parsers: parser %s is registered twice", name))
+	}
+	registry[name] = Registration{Name: name, Priority: priority, New: factory}
+}
+
+// RegisterParser adds a parser that doesn't need any deps to the registry
+func RegisterParser(name string, priority int, parser Parser) {
+	Register(name, priority, func(deps ParserDeps) Parser { return parser })
+}
+
+// Registrations returns the registered parsers that the config enables, in priority order. Parsers with the same
+// priority are ordered by name. It fails for names in the config that aren't registered.
+func Registrations(config ParserConfig) ([]Registration, error) {
+	var unknown []string
+	for _, names := range [][]string{config.Enabled, config.Disabled} {
+		for _, name := range names {
+			if _, ok := registry[name]; !ok {
+				unknown = append(unknown, name)
+			}
+		}
+	}
+	for name := range config.Priorities {
+		if _, ok := registry[name]; !ok {
+			unknown = append(unknown, name)
+		}
+	}
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return nil, fmt.Errorf("unknown parsers: %s", strings.Join(unknown, ", "))
+	}
+
+	enabled := make(map[string]bool)
+	for _, name := range config.Enabled {
+		enabled[name] = true
+	}
+	disabled := make(map[string]bool)
+	for _, name := range config.Disabled {
+		disabled[name] = true
+	}
+
+	var registrations []Registration
+	for name, registration := range registry {
+		if disabled[name] || (len(enabled) > 0 && !enabled[name]) {
+			continue
+		}
+		if priority, ok := config.Priorities[name]; ok {
+			registration.Priority = priority
+		}
+		registrations = append(registrations, registration)
+	}
+	sort.Slice(registrations, func(i, j int) bool {
+		if registrations[i].Priority != registrations[j].Priority {
+			return registrations[i].Priority < registrations[j].Priority
+		}
+		return registrations[i].Name < registrations[j].Name
+	})
+	return registrations, nil
+}
+
+// NewParsers builds the parsers that the config enables, in priority order. Parsers that can't be built with the
+// deps are left out.
+func NewParsers(deps ParserDeps, config ParserConfig) ([]Parser, error) {
+	registrations, err := Registrations(config)
+	if err != nil {
+		return nil, err
+	}
+
+	var parsers []Parser
+	for _, registration := range registrations {
+		parser := registration.New(deps)
+		if parser != nil {
+			parsers = append(parsers, parser)
+		}
+	}
+	return parsers, nil
+}
+
+// NewParserStages builds the parsers like NewParsers and groups parsers that follow each other and are never more
+// sure about a line than the same confidence into a stage
+func NewParserStages(deps ParserDeps, config ParserConfig) ([]ParserStage, error) {
+	registrations, err := Registrations(config)
+	if err != nil {
+		return nil, err
+	}
+
+	var stages []ParserStage
+	for _, registration := range registrations {
+		parser := registration.New(deps)
+		if parser == nil {
+			continue
+		}
+
+		max, ok := maxConfidence[registration.Name]
+		if !ok {
+			max = 1
+		}
+		if len(stages) == 0 || stages[len(stages)-1].MaxConfidence != max {
+			stages = append(stages, ParserStage{MaxConfidence: max})
+		}
+		stages[len(stages)-1].Parsers = append(stages[len(stages)-1].Parsers, parser)
+	}
+	return stages, nil
+}
+
+// AllParsers returns every registered parser that can be built without deps, in their default order
+func AllParsers() []Parser {
+	parsers, _ := NewParsers(ParserDeps{}, ParserConfig{})
+	return parsers
+}
@@ -0,0 +1,91 @@
+package parsers
+
+import (
+	"testing"
+
+	"github.com/evepraisal/go-evepraisal/typedb"
+	"github.com/stretchr/testify/assert"
+)
+
+func registrationNames(registrations []Registration) []string {
+	var names []string
+	for _, registration := range registrations {
+		names = append(names, registration.Name)
+	}
+	return names
+}
+
+func TestRegistrations(rt *testing.T) {
+	rt.Run("default order", func(t *testing.T) {
+		registrations, err := Registrations(ParserConfig{})
+		assert.NoError(t, err)
+		names := registrationNames(registrations)
+		assert.Len(t, names, 23)
+		assert.Equal(t, []string{"killmail", "killmail_json", "xml_fitting", "dna_fitting"}, names[:4])
+		assert.Equal(t, "heuristic", names[len(names)-1])
+	})
+
+	rt.Run("enabled and disabled", func(t *testing.T) {
+		registrations, err := Registrations(ParserConfig{
+			Enabled:  []string{"listing", "eft", "dscan"},
+			Disabled: []string{"dscan"},
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"eft", "listing"}, registrationNames(registrations))
+	})
+
+	rt.Run("priorities", func(t *testing.T) {
+		registrations, err := Registrations(ParserConfig{
+			Enabled:    []string{"listing", "eft", "multibuy"},
+			Priorities: map[string]int{"listing": 1, "multibuy": 60},
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"listing", "eft", "multibuy"}, registrationNames(registrations))
+	})
+
+	rt.Run("unknown parsers", func(t *testing.T) {
+		_, err := Registrations(ParserConfig{
+			Disabled:   []string{"eft", "nope"},
+			Priorities: map[string]int{"also_nope": 1},
+		})
+		assert.EqualError(t, err, "unknown parsers: also_nope, nope")
+	})
+}
+
+func TestNewParsers(rt *testing.T) {
+	rt.Run("without deps", func(t *testing.T) {
+		parsers, err := NewParsers(ParserDeps{}, ParserConfig{})
+		assert.NoError(t, err)
+		assert.Len(t, parsers, 20)
+	})
+
+	rt.Run("with a type database", func(t *testing.T) {
+		db := &StaticTypeDB{
+			typeNameMap: make(map[string]typedb.EveType),
+			typeIDMap:   make(map[int64]typedb.EveType),
+		}
+		db.PutType(typedb.EveType{Name: "Tritanium"})
+
+		parsers, err := NewParsers(ParserDeps{TypeDB: db}, ParserConfig{Enabled: []string{"listing", "heuristic"}})
+		assert.NoError(t, err)
+		assert.Len(t, parsers, 2)
+
+		result, rest := NewMultiParser(parsers)(StringToInput("Tritanium\nNot A Type"))
+		assert.Equal(t, []int{0}, result.Lines())
+		assert.Equal(t, Input{1: "Not A Type"}, rest)
+	})
+}
+
+func TestNewParserStages(t *testing.T) {
+	stages, err := NewParserStages(ParserDeps{}, ParserConfig{Enabled: []string{"eft", "fitting", "assets", "cargo_scan", "listing", "industry"}})
+	assert.NoError(t, err)
+
+	var confidences []float64
+	var sizes []int
+	for _, stage := range stages {
+		confidences = append(confidences, stage.MaxConfidence)
+		sizes = append(sizes, len(stage.Parsers))
+	}
+	assert.Equal(t, []float64{1, 0.75, 0.6, 0.85}, confidences)
+	assert.Equal(t, []int{2, 1, 2, 1}, sizes)
+}
@@ -13,7 +13,7 @@ type SurveyScan struct {
 }
 
 func (r *SurveyScan) Name() string {
-	return "loot_history"
+	return "survey_scan"
 }
 
 func (r *SurveyScan) Lines() []int {
@@ -32,6 +32,10 @@ var reSurveyScanner = regexp.MustCompile(strings.Join([]string{
 	`([\d,'\.]*\ (m|km))$`, // Distance
 }, ""))
 
+func init() {
+	RegisterParser("survey_scan", 150, ParseSurveyScan)
+}
+
 func ParseSurveyScan(input Input) (ParserResult, Input) {
 	surveyScan := &SurveyScan{}
 	matches, rest := regexParseLines(reSurveyScanner, input)
@@ -0,0 +1,304 @@
+package parsers
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/evepraisal/go-evepraisal/typedb"
+)
+
+// Table is the result of parsing rows from an uploaded spreadsheet
+type Table struct {
+	Items []ListingItem
+	lines []int
+}
+
+func (r *Table) Name() string {
+	return "spreadsheet"
+}
+
+func (r *Table) Lines() []int {
+	return r.lines
+}
+
+// TableColumns are the zero-based columns of a table that hold the item name, type ID and quantity. A column
+// of -1 isn't known and is detected from a header row or from the contents of the table.
+type TableColumns struct {
+	Name     int
+	TypeID   int
+	Quantity int
+}
+
+// UnknownTableColumns leaves all columns to be detected
+var UnknownTableColumns = TableColumns{Name: -1, TypeID: -1, Quantity: -1}
+
+var tableHeaders = struct {
+	Name     []string
+	TypeID   []string
+	Quantity []string
+}{
+	Name:     []string{"name", "item", "item name", "type", "type name", "typename"},
+	TypeID:   []string{"type id", "typeid", "type_id", "id"},
+	Quantity: []string{"quantity", "qty", "count", "amount", "units", "menge", "anzahl", "quantité"},
+}
+
+var reTableNumber = regexp.MustCompile(`^-?[\d,'\.\ ]+$`)
+var reTableColumnLetters = regexp.MustCompile(`^[A-Za-z]{1,2}$`)
+
+// maxTableDetectionRows limits how many rows are looked at to detect columns from their contents
+const maxTableDetectionRows = 50
+
+// TableColumnIndex resolves a column given as a one-based number, a spreadsheet column letter ("C") or the
+// text of a header cell in the first row
+func TableColumnIndex(rows [][]string, column string) (int, bool) {
+	column = strings.TrimSpace(column)
+	if column == "" {
+		return -1, false
+	}
+
+	if n, err := strconv.Atoi(column); err == nil {
+		return n - 1, n > 0
+	}
+
+	if len(rows) > 0 {
+		for i, cell := range rows[0] {
+			if strings.EqualFold(strings.TrimSpace(cell), column) {
+				return i, true
+			}
+		}
+	}
+
+	if reTableColumnLetters.MatchString(column) {
+		col := 0
+		for _, c := range strings.ToUpper(column) {
+			col = col*26 + int(c-'A'+1)
+		}
+		return col - 1, true
+	}
+	return -1, false
+}
+
+// ParseTable turns rows of a spreadsheet into items. Rows that can't be turned into an item are returned keyed
+// by their row number, which starts at 1 like in spreadsheet programs. Empty rows are skipped.
+func ParseTable(rows [][]string, columns TableColumns, typeDB typedb.TypeDB) (*Table, map[int]string) {
+	table := &Table{}
+	unparsed := make(map[int]string)
+
+	start := 0
+	if isTableHeader(rows, typeDB) {
+		columns = columnsFromHeader(rows[0], columns)
+		table.lines = append(table.lines, 0)
+		start = 1
+	}
+	columns = columnsFromContents(rows[start:], columns, typeDB)
+
+	matchgroup := make(map[ListingItem]int64)
+	for i := start; i < len(rows); i++ {
+		row := rows[i]
+		if isEmptyRow(row) {
+			continue
+		}
+
+		name, quantity, ok := tableRowItem(row, columns, typeDB)
+		if !ok {
+			unparsed[i+1] = strings.Join(row, "\t")
+			continue
+		}
+		matchgroup[ListingItem{Name: name}] += quantity
+		table.lines = append(table.lines, i)
+	}
+
+	for item, quantity := range matchgroup {
+		item.Quantity = quantity
+		table.Items = append(table.Items, item)
+	}
+	sort.Slice(table.Items, func(i, j int) bool {
+		return fmt.Sprintf("%v", table.Items[i]) < fmt.Sprintf("%v", table.Items[j])
+	})
+	return table, unparsed
+}
+
+func tableRowItem(row []string, columns TableColumns, typeDB typedb.TypeDB) (string, int64, bool) {
+	var name string
+	if columns.Name >= 0 && columns.Name < len(row) {
+		if t, ok := typeDB.GetType(CleanTypeName(row[columns.Name])); ok {
+			name = t.Name
+		}
+	}
+	if name == "" && columns.TypeID >= 0 && columns.TypeID < len(row) {
+		typeID, err := strconv.ParseInt(strings.TrimSpace(row[columns.TypeID]), 10, 64)
+		if err == nil {
+			if t, ok := typeDB.GetTypeByID(typeID); ok {
+				name = t.Name
+			}
+		}
+	}
+	if name == "" {
+		return "", 0, false
+	}
+
+	if columns.Quantity < 0 {
+		return name, 1, true
+	}
+	if columns.Quantity >= len(row) || !reTableNumber.MatchString(strings.TrimSpace(row[columns.Quantity])) {
+		return "", 0, false
+	}
+	quantity := int64(ToDecimal(strings.TrimSpace(row[columns.Quantity])))
+	if quantity <= 0 {
+		return "", 0, false
+	}
+	return name, quantity, true
+}
+
+// isTableHeader returns true if the first row only has text that isn't a type name and at least one cell
+// that looks like a known column header
+func isTableHeader(rows [][]string, typeDB typedb.TypeDB) bool {
+	if len(rows) == 0 {
+		return false
+	}
+
+	known := false
+	for _, cell := range rows[0] {
+		cell = strings.TrimSpace(cell)
+		if reTableNumber.MatchString(cell) || typeDB.HasType(cell) {
+			return false
+		}
+		if headerColumn(cell) != "" {
+			known = true
+		}
+	}
+	return known
+}
+
+func headerColumn(cell string) string {
+	cell = strings.ToLower(strings.TrimSpace(cell))
+	for _, header := range tableHeaders.Name {
+		if cell == header {
+			return "name"
+		}
+	}
+	for _, header := range tableHeaders.TypeID {
+		if cell == header {
+			return "type_id"
+		}
+	}
+	for _, header := range tableHeaders.Quantity {
+		if cell == header {
+			return "quantity"
+		}
+	}
+	return ""
+}
+
+func columnsFromHeader(header []string, columns TableColumns) TableColumns {
+	for i, cell := range header {
+		switch headerColumn(cell) {
+		case "name":
+			if columns.Name < 0 {
+				columns.Name = i
+			}
+		case "type_id":
+			if columns.TypeID < 0 {
+				columns.TypeID = i
+			}
+		case "quantity":
+			if columns.Quantity < 0 {
+				columns.Quantity = i
+			}
+		}
+	}
+	return columns
+}
+
+// columnsFromContents picks the column with the most type names as the name column (or the one with the
+// most type IDs when there are no names) and the first mostly numeric column after that as the quantity
+func columnsFromContents(rows [][]string, columns TableColumns, typeDB typedb.TypeDB) TableColumns {
+	if columns.Name >= 0 && columns.Quantity >= 0 {
+		return columns
+	}
+
+	if len(rows) > maxTableDetectionRows {
+		rows = rows[:maxTableDetectionRows]
+	}
+
+	var names, typeIDs, numbers []int
+	nonEmpty := 0
+	for _, row := range rows {
+		if isEmptyRow(row) {
+			continue
+		}
+		nonEmpty++
+		for len(names) < len(row) {
+			names = append(names, 0)
+			typeIDs = append(typeIDs, 0)
+			numbers = append(numbers, 0)
+		}
+
+		for i, cell := range row {
+			cell = strings.TrimSpace(cell)
+			if cell == "" {
+				continue
+			}
+			if reTableNumber.MatchString(cell) {
+				numbers[i]++
+				if typeID, err := strconv.ParseInt(cell, 10, 64); err == nil {
+					if _, ok := typeDB.GetTypeByID(typeID); ok {
+						typeIDs[i]++
+					}
+				}
+			} else if typeDB.HasType(CleanTypeName(cell)) {
+				names[i]++
+			}
+		}
+	}
+
+	if columns.Name < 0 && columns.TypeID < 0 {
+		columns.Name = mostCommon(names)
+		if columns.Name < 0 {
+			columns.TypeID = mostCommon(typeIDs)
+		}
+	}
+
+	if columns.Quantity < 0 {
+		itemColumn := columns.Name
+		if itemColumn < 0 {
+			itemColumn = columns.TypeID
+		}
+
+		// Prefer columns to the right of the item, a type ID column usually comes first
+		for _, after := range []int{itemColumn, -1} {
+			for i, count := range numbers {
+				if i <= after || i == columns.Name || i == columns.TypeID {
+					continue
+				}
+				if count > 0 && count*2 >= nonEmpty {
+					columns.Quantity = i
+					return columns
+				}
+			}
+		}
+	}
+	return columns
+}
+
+func mostCommon(counts []int) int {
+	best := -1
+	for i, count := range counts {
+		if count > 0 && (best < 0 || count > counts[best]) {
+			best = i
+		}
+	}
+	return best
+}
+
+func isEmptyRow(row []string) bool {
+	for _, cell := range row {
+		if strings.TrimSpace(cell) != "" {
+			return false
+		}
+	}
+	return true
+}
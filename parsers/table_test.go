@@ -0,0 +1,120 @@
+package parsers
+
+import (
+	"testing"
+
+	"github.com/evepraisal/go-evepraisal/typedb"
+	"github.com/stretchr/testify/assert"
+)
+
+var tableTypes = []typedb.EveType{
+	{ID: 34, Name: "Tritanium"},
+	{ID: 35, Name: "Pyerite"},
+	{ID: 587, Name: "Rifter"},
+}
+
+var TableParserCases = []struct {
+	name     string
+	rows     [][]string
+	columns  TableColumns
+	result   *Table
+	unparsed map[int]string
+}{
+	{
+		"Header row",
+		[][]string{
+			{"Location", "Item", "Qty", "Price"},
+			{"Jita", "Tritanium", "1,000", "5.5"},
+			{"Jita", "pyerite", "20", "10"},
+			{"", "", "", ""},
+			{"Amarr", "Not A Thing", "1", "1"},
+			{"Amarr", "Tritanium", "500", "5.5"},
+		},
+		UnknownTableColumns,
+		&Table{
+			Items: []ListingItem{
+				{Name: "Pyerite", Quantity: 20},
+				{Name: "Tritanium", Quantity: 1500},
+			},
+			lines: []int{0, 1, 2, 5},
+		},
+		map[int]string{5: "Amarr\tNot A Thing\t1\t1"},
+	}, {
+		"Type IDs without header",
+		[][]string{
+			{"587", "2", "Shiny"},
+			{"34", "100", "Rocks"},
+			{"99999", "1", "Unknown"},
+		},
+		UnknownTableColumns,
+		&Table{
+			Items: []ListingItem{
+				{Name: "Rifter", Quantity: 2},
+				{Name: "Tritanium", Quantity: 100},
+			},
+			lines: []int{0, 1},
+		},
+		map[int]string{3: "99999\t1\tUnknown"},
+	}, {
+		"Type ID column before names",
+		[][]string{
+			{"34", "Tritanium", "7"},
+			{"35", "Pyerite", "8"},
+		},
+		UnknownTableColumns,
+		&Table{
+			Items: []ListingItem{
+				{Name: "Pyerite", Quantity: 8},
+				{Name: "Tritanium", Quantity: 7},
+			},
+			lines: []int{0, 1},
+		},
+		map[int]string{},
+	}, {
+		"Explicit columns",
+		[][]string{
+			{"10", "Tritanium", "3"},
+			{"20", "Pyerite", "x"},
+		},
+		TableColumns{Name: 1, TypeID: -1, Quantity: 0},
+		&Table{
+			Items: []ListingItem{
+				{Name: "Pyerite", Quantity: 20},
+				{Name: "Tritanium", Quantity: 10},
+			},
+			lines: []int{0, 1},
+		},
+		map[int]string{},
+	},
+}
+
+func TestParseTable(rt *testing.T) {
+	for _, c := range TableParserCases {
+		rt.Run(c.name, func(t *testing.T) {
+			db := &StaticTypeDB{
+				typeNameMap: make(map[string]typedb.EveType),
+				typeIDMap:   make(map[int64]typedb.EveType),
+			}
+			for _, t := range tableTypes {
+				db.PutType(t)
+			}
+			result, unparsed := ParseTable(c.rows, c.columns, db)
+			assert.Equal(t, c.result, result, "results should be the same")
+			assert.Equal(t, c.unparsed, unparsed, "the unparsed rows should be the same")
+		})
+	}
+}
+
+func TestTableColumnIndex(t *testing.T) {
+	rows := [][]string{{"Name", "Quantity"}}
+	for column, expected := range map[string]int{"2": 1, "quantity": 1, "A": 0, "AB": 27} {
+		idx, ok := TableColumnIndex(rows, column)
+		assert.True(t, ok, column)
+		assert.Equal(t, expected, idx, column)
+	}
+
+	_, ok := TableColumnIndex(rows, "0")
+	assert.False(t, ok)
+	_, ok = TableColumnIndex(rows, "price estimate")
+	assert.False(t, ok)
+}
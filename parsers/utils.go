@@ -31,6 +31,37 @@ func ToFloat64(s string) float64 {
 	return f
 }
 
+var cleanDecimals = regexp.MustCompile(`[,\'\ ` + "\xc2\xa0" + `]`)
+var cleanGroupSeparators = regexp.MustCompile(`[\'\ ` + "\xc2\xa0" + `]`)
+
+// ToDecimal parses numbers that have a fractional part and may use thousands separators, like "3,703.5". Numbers
+// that use a decimal comma, like "3.703,5" or "12,5", are understood too. A single comma followed by three
+// digits is ambiguous and read as a thousands separator.
+func ToDecimal(s string) float64 {
+	s = cleanGroupSeparators.ReplaceAllString(s, "")
+	lastComma := strings.LastIndex(s, ",")
+	lastDot := strings.LastIndex(s, ".")
+	decimalComma := false
+	switch {
+	case lastComma != -1 && lastDot != -1:
+		decimalComma = lastComma > lastDot
+	case lastComma != -1:
+		decimalComma = strings.Count(s, ",") == 1 && len(s)-lastComma-1 != 3
+	case lastDot != -1:
+		// "1.234.567" can only be grouped digits
+		if strings.Count(s, ".") > 1 {
+			s = strings.Replace(s, ".", "", -1)
+		}
+	}
+
+	if decimalComma {
+		s = strings.Replace(s, ".", "", -1)
+		s = strings.Replace(s, ",", ".", 1)
+		return ToFloat64(s)
+	}
+	return ToFloat64(cleanDecimals.ReplaceAllString(s, ""))
+}
+
 func CleanTypeName(s string) string {
 	return strings.TrimSuffix(strings.Trim(s, " "), "*")
 }
@@ -25,6 +25,7 @@ type ViewContentsItem struct {
 	Group    string
 	Location string
 	Quantity int64
+	Path     string
 }
 
 var reViewContents = regexp.MustCompile(strings.Join([]string{
@@ -40,6 +41,10 @@ var reViewContents2 = regexp.MustCompile(strings.Join([]string{
 	`([\d,'\.]+)$`, // quantity
 }, ""))
 
+func init() {
+	RegisterParser("view_contents", 130, ParseViewContents)
+}
+
 func ParseViewContents(input Input) (ParserResult, Input) {
 	viewContents := &ViewContents{}
 	matches, rest := regexParseLines(reViewContents, input)
@@ -47,20 +52,34 @@ func ParseViewContents(input Input) (ParserResult, Input) {
 	viewContents.lines = append(viewContents.lines, regexMatchedLines(matches)...)
 	viewContents.lines = append(viewContents.lines, regexMatchedLines(matches2)...)
 
+	names := make(map[int]string, len(matches)+len(matches2))
+	for i, match := range matches {
+		names[i] = CleanTypeName(match[1])
+	}
+	for i, match := range matches2 {
+		names[i] = CleanTypeName(match[1])
+	}
+	paths, headers := containerPaths(input, names)
+	if len(headers) > 0 {
+		viewContents.lines = claimHeaders(viewContents.lines, rest, headers)
+	}
+
 	matchgroup := make(map[ViewContentsItem]int64)
-	for _, match := range matches {
+	for i, match := range matches {
 		item := ViewContentsItem{
 			Name:     CleanTypeName(match[1]),
 			Group:    match[2],
 			Location: match[3],
+			Path:     paths[i],
 		}
 		matchgroup[item] += ToInt(match[4])
 	}
 
-	for _, match := range matches2 {
+	for i, match := range matches2 {
 		item := ViewContentsItem{
 			Name:  CleanTypeName(match[1]),
 			Group: match[2],
+			Path:  paths[i],
 		}
 		matchgroup[item] += ToInt(match[3])
 	}
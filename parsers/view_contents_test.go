@@ -53,5 +53,24 @@ Men's 'Esquire' Coat (red/gold)	Outer	1`,
 			lines: []int{0}},
 		Input{},
 		true,
+	}, {
+		"Nested containers",
+		`Large Micro Jump Drive	Micro Jump Drive	Cargo Hold	1
+Giant Secure Container	Secure Cargo Container	Cargo Hold	1
+  Nanite Repair Paste	Nanite Repair Paste	Cargo Hold	100
+  Small Secure Container	Secure Cargo Container	Cargo Hold	1
+    Nanite Repair Paste	Nanite Repair Paste	Cargo Hold	50
+Bouncer II	Combat Drone	Drone Bay	1`,
+		&ViewContents{
+			Items: []ViewContentsItem{
+				{Name: "Bouncer II", Group: "Combat Drone", Location: "Drone Bay", Quantity: 1},
+				{Name: "Giant Secure Container", Group: "Secure Cargo Container", Location: "Cargo Hold", Quantity: 1},
+				{Name: "Large Micro Jump Drive", Group: "Micro Jump Drive", Location: "Cargo Hold", Quantity: 1},
+				{Name: "Nanite Repair Paste", Group: "Nanite Repair Paste", Location: "Cargo Hold", Quantity: 100, Path: "Giant Secure Container"},
+				{Name: "Nanite Repair Paste", Group: "Nanite Repair Paste", Location: "Cargo Hold", Quantity: 50, Path: "Giant Secure Container > Small Secure Container"},
+				{Name: "Small Secure Container", Group: "Secure Cargo Container", Location: "Cargo Hold", Quantity: 1, Path: "Giant Secure Container"}},
+			lines: []int{0, 1, 2, 3, 4, 5}},
+		Input{},
+		false,
 	},
 }
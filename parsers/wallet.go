@@ -14,7 +14,7 @@ type Wallet struct {
 }
 
 func (r *Wallet) Name() string {
-	return "view_contents"
+	return "wallet"
 }
 
 func (r *Wallet) Lines() []int {
@@ -59,6 +59,10 @@ var reWallet2 = regexp.MustCompile(strings.Join([]string{
 	`([\S ]+)$`,                           // location
 }, ""))
 
+func init() {
+	RegisterParser("wallet", 140, ParseWallet)
+}
+
 func ParseWallet(input Input) (ParserResult, Input) {
 	wallet := &Wallet{}
 	matches, rest := regexParseLines(reWallet, input)
@@ -28,5 +28,19 @@ var walletTestCases = []Case{
 			lines: []int{0}},
 		Input{},
 		true,
+	}, {
+		"Journal and itemized transactions",
+		`2014.01.04 05:49:31	Market Escrow	-251.00 ISK	325.22 ISK	Market escrow authorized by: Me
+2014.01.04 16:08	Storm Command Center	200,000.00 ISK	2	-400,000.00 ISK	ISK	lady scarlette	Otanuomi IV - Moon 4 - Ishukone Corporation Factory
+2014.01.05 10:11	Storm Command Center	250,000.00 ISK	1	250,000.00 ISK	ISK	Ormand Ishikela	Jita IV - Moon 4 - Caldari Navy Assembly Plant`,
+		&Wallet{
+			Transactions: []WalletTransaction{
+				{Datetime: "2014.01.04 05:49:31", TransactionType: "Market Escrow", Amount: "-251.00 ISK", Balance: "325.22 ISK", Description: "Market escrow authorized by: Me"}},
+			ItemizedTransactions: []WalletItemizedTransaction{
+				{Datetime: "2014.01.04 16:08", Name: "Storm Command Center", Price: "200,000.00 ISK", Quantity: 2, Credit: "-400,000.00 ISK", Currency: "ISK", Client: "lady scarlette", Location: "Otanuomi IV - Moon 4 - Ishukone Corporation Factory"},
+				{Datetime: "2014.01.05 10:11", Name: "Storm Command Center", Price: "250,000.00 ISK", Quantity: 1, Credit: "250,000.00 ISK", Currency: "ISK", Client: "Ormand Ishikela", Location: "Jita IV - Moon 4 - Caldari Navy Assembly Plant"}},
+			lines: []int{0, 1, 2}},
+		Input{},
+		true,
 	},
 }
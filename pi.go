@@ -0,0 +1,173 @@
+package evepraisal
+
+import (
+	"sort"
+
+	"github.com/evepraisal/go-evepraisal/parsers"
+	"github.com/evepraisal/go-evepraisal/typedb"
+	"github.com/spf13/viper"
+)
+
+// Tiers of planetary commodities by group. Raw resources are tier 0.
+var piTiers = map[int64]int{
+	1032: 0, // Planet Solid - Raw Resource
+	1033: 0, // Planet Liquid-Gas - Raw Resource
+	1035: 0, // Planet Organic - Raw Resource
+	1042: 1, // Basic Commodities
+	1034: 2, // Refined Commodities
+	1040: 3, // Specialized Commodities
+	1041: 4, // Advanced Commodities
+}
+
+// piBaseCosts are the values per unit that customs offices charge their tax on, by tier
+var piBaseCosts = []float64{5, 400, 7200, 60000, 1200000}
+
+// PIPlan shows for every commodity of a PI appraisal what it is worth as it is and what it could be processed into
+// at the next tier. Export tax is what a customs office with the pi-customs-tax rate (in percent) charges to
+// launch the commodity off the planet.
+type PIPlan struct {
+	TaxRate     float64       `json:"tax_rate"`
+	Commodities []PICommodity `json:"commodities"`
+}
+
+type PICommodity struct {
+	TypeID    int64             `json:"typeID"`
+	Name      string            `json:"name"`
+	Tier      int               `json:"tier"`
+	Quantity  int64             `json:"quantity"`
+	Value     float64           `json:"value"`
+	ExportTax float64           `json:"export_tax"`
+	Options   []PIProcessOption `json:"options,omitempty"`
+}
+
+// PIProcessOption is a schematic that the commodity is an input for. The values and the export tax are per
+// cycle, Cycles is how many cycles the commodity is enough for and Profit is what a cycle makes after buying the
+// inputs and exporting the output.
+type PIProcessOption struct {
+	Schematic   string        `json:"schematic"`
+	CycleTime   int64         `json:"cycle_time"`
+	Inputs      []PIComponent `json:"inputs"`
+	Output      PIComponent   `json:"output"`
+	InputValue  float64       `json:"input_value"`
+	OutputValue float64       `json:"output_value"`
+	ExportTax   float64       `json:"export_tax"`
+	Profit      float64       `json:"profit"`
+	Cycles      int64         `json:"cycles"`
+}
+
+type PIComponent struct {
+	TypeID   int64   `json:"typeID"`
+	Name     string  `json:"name"`
+	Quantity int64   `json:"quantity"`
+	Value    float64 `json:"value"`
+}
+
+// PIExportTax is the customs office tax for launching the given quantity of a commodity
+func PIExportTax(t typedb.EveType, quantity int64, taxRate float64) float64 {
+	tier, ok := piTiers[t.GroupID]
+	if !ok {
+		return 0
+	}
+	return float64(quantity) * piBaseCosts[tier] * taxRate / 100
+}
+
+// newPIPlan returns the plan for the PI commodities in the parser result, or nil if there aren't any. The items
+// are the priced appraisal items.
+func (app *App) newPIPlan(result parsers.ParserResult, items []AppraisalItem, market string) *PIPlan {
+	if !hasPIResult(result) {
+		return nil
+	}
+
+	plan := &PIPlan{TaxRate: viper.GetFloat64("pi-customs-tax")}
+	for _, item := range items {
+		t, ok := app.TypeDB.GetTypeByID(item.TypeID)
+		if !ok {
+			continue
+		}
+
+		tier, ok := piTiers[t.GroupID]
+		if !ok {
+			continue
+		}
+
+		commodity := PICommodity{
+			TypeID:    t.ID,
+			Name:      t.Name,
+			Tier:      tier,
+			Quantity:  item.Quantity,
+			Value:     item.RepresentativePrice(),
+			ExportTax: PIExportTax(t, item.Quantity, plan.TaxRate),
+		}
+
+		for _, schematic := range t.Schematics {
+			option := PIProcessOption{
+				Schematic: schematic.Name,
+				CycleTime: schematic.CycleTime,
+				Output:    app.piComponent(schematic.Output, market),
+			}
+			for _, input := range schematic.Inputs {
+				component := app.piComponent(input, market)
+				option.Inputs = append(option.Inputs, component)
+				option.InputValue += component.Value
+				if input.TypeID == t.ID && input.Quantity > 0 {
+					option.Cycles = item.Quantity / input.Quantity
+				}
+			}
+			option.OutputValue = option.Output.Value
+			if output, ok := app.TypeDB.GetTypeByID(schematic.Output.TypeID); ok {
+				option.ExportTax = PIExportTax(output, schematic.Output.Quantity, plan.TaxRate)
+			}
+			option.Profit = option.OutputValue - option.ExportTax - option.InputValue
+			commodity.Options = append(commodity.Options, option)
+		}
+		sort.Slice(commodity.Options, func(i, j int) bool {
+			return commodity.Options[i].Profit > commodity.Options[j].Profit
+		})
+
+		plan.Commodities = append(plan.Commodities, commodity)
+	}
+
+	sort.Slice(plan.Commodities, func(i, j int) bool {
+		if plan.Commodities[i].Tier != plan.Commodities[j].Tier {
+			return plan.Commodities[i].Tier < plan.Commodities[j].Tier
+		}
+		return plan.Commodities[i].Name < plan.Commodities[j].Name
+	})
+	return plan
+}
+
+func (app *App) piComponent(component typedb.Component, market string) PIComponent {
+	c := PIComponent{TypeID: component.TypeID, Quantity: component.Quantity}
+	t, ok := app.TypeDB.GetTypeByID(component.TypeID)
+	if !ok {
+		return c
+	}
+	c.Name = t.Name
+	c.Value = float64(component.Quantity) * app.typePrice(market, t)
+	return c
+}
+
+// typePrice is the price of a single unit of the type, as it is used for appraisal items
+func (app *App) typePrice(market string, t typedb.EveType) float64 {
+	item := AppraisalItem{Name: t.Name, TypeID: t.ID, TypeName: t.Name, Quantity: 1}
+	prices, err := app.PricesForItem(market, item)
+	if err != nil {
+		return 0
+	}
+	item.Prices = prices
+	return item.SingleRepresentativePrice()
+}
+
+func hasPIResult(result parsers.ParserResult) bool {
+	switch r := result.(type) {
+	case *parsers.PI:
+		return true
+	case *parsers.MultiParserResult:
+		for _, subResult := range r.Results {
+			if hasPIResult(subResult) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,86 @@
+package evepraisal
+
+import (
+	"testing"
+
+	"github.com/evepraisal/go-evepraisal/parsers"
+	"github.com/evepraisal/go-evepraisal/typedb"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPIExportTax(t *testing.T) {
+	assert.Equal(t, 15000.0, PIExportTax(typedb.EveType{GroupID: 1035}, 30000, 10))
+	assert.Equal(t, 40000.0, PIExportTax(typedb.EveType{GroupID: 1042}, 1000, 10))
+	assert.Equal(t, 3600.0, PIExportTax(typedb.EveType{GroupID: 1034}, 5, 10))
+	assert.Equal(t, 600000.0, PIExportTax(typedb.EveType{GroupID: 1041}, 1, 50))
+	assert.Equal(t, 0.0, PIExportTax(typedb.EveType{GroupID: 18}, 1000, 10))
+}
+
+func TestPIPlan(rt *testing.T) {
+	defer viper.Set("pi-customs-tax", viper.Get("pi-customs-tax"))
+	viper.Set("pi-customs-tax", 10.0)
+
+	bacteriaInputs := []typedb.Component{{TypeID: 2393, Quantity: 40}, {TypeID: 2397, Quantity: 40}}
+	app := newTestApp(
+		fakePriceDB{2073: {sell: 3}, 2393: {sell: 400}, 2397: {sell: 450}, 3693: {sell: 20000}, 3697: {sell: 15000}, 34: {sell: 5}},
+		typedb.EveType{ID: 2073, Name: "Microorganisms", GroupID: 1035, Schematics: []typedb.Schematic{
+			{Name: "Bacteria", CycleTime: 1800, Inputs: []typedb.Component{{TypeID: 2073, Quantity: 3000}}, Output: typedb.Component{TypeID: 2393, Quantity: 20}},
+		}},
+		typedb.EveType{ID: 2393, Name: "Bacteria", GroupID: 1042, Schematics: []typedb.Schematic{
+			{Name: "Polytextiles", CycleTime: 3600, Inputs: bacteriaInputs, Output: typedb.Component{TypeID: 3697, Quantity: 5}},
+			{Name: "Fertilizer", CycleTime: 3600, Inputs: bacteriaInputs, Output: typedb.Component{TypeID: 3693, Quantity: 5}},
+		}},
+		typedb.EveType{ID: 2397, Name: "Proteins", GroupID: 1042},
+		typedb.EveType{ID: 3693, Name: "Fertilizer", GroupID: 1034},
+		typedb.EveType{ID: 3697, Name: "Polytextiles", GroupID: 1034},
+		typedb.EveType{ID: 34, Name: "Tritanium", GroupID: 18},
+	)
+	priced := func(typeID int64, name string, quantity int64, price float64) AppraisalItem {
+		item := AppraisalItem{TypeID: typeID, Name: name, Quantity: quantity}
+		item.Prices.Sell.Min = price
+		return item
+	}
+	items := []AppraisalItem{
+		priced(2393, "Bacteria", 1000, 400),
+		priced(34, "Tritanium", 100, 5),
+		priced(2073, "Microorganisms", 30000, 3),
+	}
+
+	rt.Run("not PI", func(t *testing.T) {
+		assert.Nil(t, app.newPIPlan(&parsers.Listing{}, items, "jita"))
+	})
+
+	rt.Run("processing options", func(t *testing.T) {
+		bacteria := PIComponent{TypeID: 2393, Name: "Bacteria", Quantity: 40, Value: 16000}
+		proteins := PIComponent{TypeID: 2397, Name: "Proteins", Quantity: 40, Value: 18000}
+		assert.Equal(t, &PIPlan{
+			TaxRate: 10,
+			Commodities: []PICommodity{
+				{
+					TypeID: 2073, Name: "Microorganisms", Tier: 0, Quantity: 30000, Value: 90000, ExportTax: 15000,
+					Options: []PIProcessOption{{
+						Schematic: "Bacteria", CycleTime: 1800,
+						Inputs:     []PIComponent{{TypeID: 2073, Name: "Microorganisms", Quantity: 3000, Value: 9000}},
+						Output:     PIComponent{TypeID: 2393, Name: "Bacteria", Quantity: 20, Value: 8000},
+						InputValue: 9000, OutputValue: 8000, ExportTax: 800, Profit: -1800, Cycles: 10,
+					}},
+				}, {
+					TypeID: 2393, Name: "Bacteria", Tier: 1, Quantity: 1000, Value: 400000, ExportTax: 40000,
+					// The most profitable schematic comes first
+					Options: []PIProcessOption{{
+						Schematic: "Fertilizer", CycleTime: 3600,
+						Inputs:     []PIComponent{bacteria, proteins},
+						Output:     PIComponent{TypeID: 3693, Name: "Fertilizer", Quantity: 5, Value: 100000},
+						InputValue: 34000, OutputValue: 100000, ExportTax: 3600, Profit: 62400, Cycles: 25,
+					}, {
+						Schematic: "Polytextiles", CycleTime: 3600,
+						Inputs:     []PIComponent{bacteria, proteins},
+						Output:     PIComponent{TypeID: 3697, Name: "Polytextiles", Quantity: 5, Value: 75000},
+						InputValue: 34000, OutputValue: 75000, ExportTax: 3600, Profit: 37400, Cycles: 25,
+					}},
+				},
+			},
+		}, app.newPIPlan(&parsers.MultiParserResult{Results: []parsers.ParserResult{&parsers.PI{}}}, items, "jita"))
+	})
+}
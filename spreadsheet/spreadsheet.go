@@ -0,0 +1,132 @@
+package spreadsheet
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"path/filepath"
+	"strings"
+)
+
+// Format is a kind of structured file that can be read into rows
+type Format string
+
+const (
+	Unknown Format = ""
+	CSV     Format = "csv"
+	TSV     Format = "tsv"
+	XLSX    Format = "xlsx"
+)
+
+var contentTypes = map[string]Format{
+	"text/csv":                    CSV,
+	"application/csv":             CSV,
+	"text/comma-separated-values": CSV,
+	"text/tab-separated-values":   TSV,
+	"application/vnd.openxmlformats-officedocument.spreadsheetml.sheet": XLSX,
+}
+
+var extensions = map[string]Format{
+	".csv":  CSV,
+	".tsv":  TSV,
+	".tab":  TSV,
+	".xlsx": XLSX,
+}
+
+// Detect returns the format of a file from its content type, its filename extension or, for XLSX files, its
+// contents. Anything else is Unknown and should be treated as pasted text.
+func Detect(filename string, contentType string, data []byte) Format {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err == nil {
+		if format, ok := contentTypes[strings.ToLower(mediaType)]; ok {
+			return format
+		}
+	}
+
+	if format, ok := extensions[strings.ToLower(filepath.Ext(filename))]; ok {
+		return format
+	}
+
+	// XLSX files are zip archives
+	if bytes.HasPrefix(data, []byte("PK\x03\x04")) {
+		return XLSX
+	}
+	return Unknown
+}
+
+// ErrTooManyRows is returned by Read when a file has more rows than allowed
+var ErrTooManyRows = errors.New("too many rows")
+
+// ErrTooManyColumns is returned by Read when a cell is further right than MaxColumns
+var ErrTooManyColumns = errors.New("too many columns")
+
+// MaxColumns is the most cells a row may have. Item lists only need a handful of columns, and XLSX rows are
+// padded out to the last referenced cell.
+const MaxColumns = 256
+
+// Read returns the rows of a file. Rows don't need to have the same number of cells. Files with more than
+// maxRows rows return ErrTooManyRows.
+func Read(format Format, data []byte, maxRows int) ([][]string, error) {
+	var (
+		rows [][]string
+		err  error
+	)
+	switch format {
+	case CSV:
+		rows, err = readDelimited(data, ',')
+	case TSV:
+		rows, err = readDelimited(data, '\t')
+	case XLSX:
+		rows, err = readXLSX(data, maxRows)
+	default:
+		return nil, fmt.Errorf("unsupported format %q", format)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) > maxRows {
+		return nil, ErrTooManyRows
+	}
+	return rows, nil
+}
+
+func readDelimited(data []byte, comma rune) ([][]string, error) {
+	// Spreadsheet programs like to add a byte order mark
+	data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))
+
+	r := csv.NewReader(bytes.NewReader(data))
+	r.Comma = comma
+	r.FieldsPerRecord = -1
+	r.LazyQuotes = true
+	if comma == ',' && bytes.Count(data, []byte(";")) > bytes.Count(data, []byte(",")) {
+		// Locales that use a decimal comma export CSV with semicolons
+		r.Comma = ';'
+	}
+	return r.ReadAll()
+}
+
+// Write writes the rows as a file in the given format
+func Write(w io.Writer, format Format, rows [][]string) error {
+	switch format {
+	case CSV:
+		return writeDelimited(w, rows, ',')
+	case TSV:
+		return writeDelimited(w, rows, '\t')
+	case XLSX:
+		return writeXLSX(w, rows)
+	}
+	return fmt.Errorf("unsupported format %q", format)
+}
+
+func writeDelimited(w io.Writer, rows [][]string, comma rune) error {
+	cw := csv.NewWriter(w)
+	cw.Comma = comma
+	err := cw.WriteAll(rows)
+	if err != nil {
+		return err
+	}
+	return cw.Error()
+}
@@ -0,0 +1,148 @@
+package spreadsheet
+
+import (
+	"archive/zip"
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDetect(t *testing.T) {
+	assert.Equal(t, CSV, Detect("", "text/csv; charset=utf-8", nil))
+	assert.Equal(t, TSV, Detect("inventory.TSV", "application/octet-stream", nil))
+	assert.Equal(t, XLSX, Detect("upload", "", []byte("PK\x03\x04rest")))
+	assert.Equal(t, Unknown, Detect("paste.txt", "text/plain", []byte("Tritanium 5")))
+}
+
+func TestReadCSV(t *testing.T) {
+	rows, err := Read(CSV, []byte("\xef\xbb\xbfName,Qty\nTritanium,\"1,000\"\nPyerite\n"), 100)
+	assert.NoError(t, err)
+	assert.Equal(t, [][]string{{"Name", "Qty"}, {"Tritanium", "1,000"}, {"Pyerite"}}, rows)
+
+	rows, err = Read(CSV, []byte("Name;Qty\nTritanium;1,5\n"), 100)
+	assert.NoError(t, err)
+	assert.Equal(t, [][]string{{"Name", "Qty"}, {"Tritanium", "1,5"}}, rows)
+}
+
+func TestReadXLSX(t *testing.T) {
+	files := map[string]string{
+		"xl/workbook.xml": `<?xml version="1.0" encoding="UTF-8"?>
+<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">
+  <sheets><sheet name="Inventory" sheetId="1" r:id="rId3"/></sheets>
+</workbook>`,
+		"xl/_rels/workbook.xml.rels": `<?xml version="1.0" encoding="UTF-8"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">
+  <Relationship Id="rId3" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/inventory.xml"/>
+</Relationships>`,
+		"xl/sharedStrings.xml": `<?xml version="1.0" encoding="UTF-8"?>
+<sst xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">
+  <si><t>Item</t></si>
+  <si><t>Quantity</t></si>
+  <si><r><t>Trit</t></r><r><t>anium</t></r></si>
+</sst>`,
+		"xl/worksheets/inventory.xml": `<?xml version="1.0" encoding="UTF-8"?>
+<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">
+  <sheetData>
+    <row r="1"><c r="A1" t="s"><v>0</v></c><c r="B1" t="s"><v>1</v></c></row>
+    <row r="2"><c r="A2" t="s"><v>2</v></c><c r="B2"><v>1500</v></c></row>
+    <row r="4"><c r="A4" t="inlineStr"><is><t>Pyerite</t></is></c><c r="C4"><v>7</v></c></row>
+  </sheetData>
+</worksheet>`,
+	}
+
+	rows, err := Read(XLSX, zipFiles(t, files), 100)
+	assert.NoError(t, err)
+	assert.Equal(t, [][]string{
+		{"Item", "Quantity"},
+		{"Tritanium", "1500"},
+		nil,
+		{"Pyerite", "", "7"},
+	}, rows)
+}
+
+func TestWriteXLSX(t *testing.T) {
+	rows := [][]string{
+		{"Name", "Quantity", "Note"},
+		{"Tritanium", "1500", "<cheap & plenty>"},
+	}
+	var buf bytes.Buffer
+	assert.NoError(t, Write(&buf, XLSX, rows))
+
+	read, err := Read(XLSX, buf.Bytes(), 100)
+	assert.NoError(t, err)
+	assert.Equal(t, rows, read)
+	assert.Equal(t, "AB", columnName(27))
+}
+
+func TestWriteCSV(t *testing.T) {
+	var buf bytes.Buffer
+	assert.NoError(t, Write(&buf, CSV, [][]string{{"Name", "Quantity"}, {"Tritanium", "1,500"}}))
+	assert.Equal(t, "Name,Quantity\nTritanium,\"1,500\"\n", buf.String())
+}
+
+func TestReadXLSXTooBig(t *testing.T) {
+	var buf bytes.Buffer
+	z := zip.NewWriter(&buf)
+	w, err := z.Create("xl/workbook.xml")
+	assert.NoError(t, err)
+	// Compresses to a few kilobytes
+	_, err = w.Write(bytes.Repeat([]byte(" "), maxXLSXEntrySize+1))
+	assert.NoError(t, err)
+	assert.NoError(t, z.Close())
+	assert.True(t, buf.Len() < 1000*1000)
+
+	_, err = Read(XLSX, buf.Bytes(), 100)
+	assert.EqualError(t, err, "xl/workbook.xml is too big")
+}
+
+func TestReadTooManyRows(t *testing.T) {
+	_, err := Read(CSV, []byte("Tritanium\nPyerite\nMexallon\n"), 2)
+	assert.Equal(t, ErrTooManyRows, err)
+}
+
+func TestReadXLSXSparse(t *testing.T) {
+	sheet := func(rows string) []byte {
+		return zipFiles(t, map[string]string{
+			"xl/workbook.xml": `<?xml version="1.0" encoding="UTF-8"?>
+<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">
+  <sheets><sheet name="Sheet1" sheetId="1" r:id="rId1"/></sheets>
+</workbook>`,
+			"xl/_rels/workbook.xml.rels": `<?xml version="1.0" encoding="UTF-8"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">
+  <Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/>
+</Relationships>`,
+			"xl/worksheets/sheet1.xml": `<?xml version="1.0" encoding="UTF-8"?>
+<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">
+  <sheetData>` + rows + `</sheetData>
+</worksheet>`,
+		})
+	}
+
+	_, err := Read(XLSX, sheet(`<row r="30000000"><c r="A30000000"><v>1</v></c></row>`), 10000)
+	assert.Equal(t, ErrTooManyRows, err)
+
+	_, err = Read(XLSX, sheet(`<row r="1"><c r="XFD1"><v>1</v></c></row>`), 10000)
+	assert.Equal(t, ErrTooManyColumns, err)
+
+	_, err = Read(XLSX, sheet(`<row r="1"><c r="ZZZZZZZZZZZZZZZZZZZZ1"><v>1</v></c></row>`), 10000)
+	assert.Equal(t, ErrTooManyColumns, err)
+
+	rows, err := Read(XLSX, sheet(`<row r="3"><c r="1"><v>5</v></c><c r="IV3"><v>7</v></c></row>`), 3)
+	assert.NoError(t, err)
+	assert.Len(t, rows, 3)
+	assert.Equal(t, "5", rows[2][0])
+	assert.Equal(t, "7", rows[2][MaxColumns-1])
+}
+
+func zipFiles(t *testing.T, files map[string]string) []byte {
+	var buf bytes.Buffer
+	z := zip.NewWriter(&buf)
+	for name, content := range files {
+		w, err := z.Create(name)
+		assert.NoError(t, err)
+		w.Write([]byte(content))
+	}
+	assert.NoError(t, z.Close())
+	return buf.Bytes()
+}
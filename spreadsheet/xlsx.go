@@ -0,0 +1,290 @@
+package spreadsheet
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+type xlsxWorkbook struct {
+	Sheets []struct {
+		Name string `xml:"name,attr"`
+		RID  string `xml:"http://schemas.openxmlformats.org/officeDocument/2006/relationships id,attr"`
+	} `xml:"sheets>sheet"`
+}
+
+type xlsxRelationships struct {
+	Relationships []struct {
+		ID     string `xml:"Id,attr"`
+		Target string `xml:"Target,attr"`
+	} `xml:"Relationship"`
+}
+
+type xlsxSharedStrings struct {
+	Items []xlsxRichText `xml:"si"`
+}
+
+type xlsxRichText struct {
+	Text string `xml:"t"`
+	Runs []struct {
+		Text string `xml:"t"`
+	} `xml:"r"`
+}
+
+func (t xlsxRichText) String() string {
+	if len(t.Runs) == 0 {
+		return t.Text
+	}
+	var s bytes.Buffer
+	for _, run := range t.Runs {
+		s.WriteString(run.Text)
+	}
+	return s.String()
+}
+
+type xlsxWorksheet struct {
+	Rows []struct {
+		Index int `xml:"r,attr"`
+		Cells []struct {
+			Ref    string       `xml:"r,attr"`
+			Type   string       `xml:"t,attr"`
+			Value  string       `xml:"v"`
+			Inline xlsxRichText `xml:"is"`
+		} `xml:"c"`
+	} `xml:"sheetData>row"`
+}
+
+// readXLSX returns the rows of the first worksheet of a workbook. Empty rows in between are kept so that row
+// numbers match the ones shown by spreadsheet programs.
+func readXLSX(data []byte, maxRows int) ([][]string, error) {
+	z, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("not an xlsx file: %s", err)
+	}
+
+	files := make(map[string]*zip.File)
+	for _, f := range z.File {
+		files[f.Name] = f
+	}
+
+	sheetPath, err := firstSheetPath(files)
+	if err != nil {
+		return nil, err
+	}
+
+	var sharedStrings xlsxSharedStrings
+	if _, ok := files["xl/sharedStrings.xml"]; ok {
+		err = decodeZipXML(files, "xl/sharedStrings.xml", &sharedStrings)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var sheet xlsxWorksheet
+	err = decodeZipXML(files, sheetPath, &sheet)
+	if err != nil {
+		return nil, err
+	}
+
+	var rows [][]string
+	for _, row := range sheet.Rows {
+		rowIndex := len(rows)
+		if row.Index > 0 {
+			rowIndex = row.Index - 1
+		}
+		// Row and cell references can point anywhere, so check them before padding out to them
+		if rowIndex >= maxRows {
+			return nil, ErrTooManyRows
+		}
+		for len(rows) <= rowIndex {
+			rows = append(rows, nil)
+		}
+
+		var cells []string
+		for i, cell := range row.Cells {
+			col := i
+			if cell.Ref != "" && columnIndex(cell.Ref) >= 0 {
+				col = columnIndex(cell.Ref)
+			}
+			if col >= MaxColumns {
+				return nil, ErrTooManyColumns
+			}
+			for len(cells) <= col {
+				cells = append(cells, "")
+			}
+
+			switch cell.Type {
+			case "s":
+				idx, err := strconv.Atoi(cell.Value)
+				if err == nil && idx >= 0 && idx < len(sharedStrings.Items) {
+					cells[col] = sharedStrings.Items[idx].String()
+				}
+			case "inlineStr":
+				cells[col] = cell.Inline.String()
+			default:
+				cells[col] = cell.Value
+			}
+		}
+		rows[rowIndex] = cells
+	}
+	return rows, nil
+}
+
+func firstSheetPath(files map[string]*zip.File) (string, error) {
+	var workbook xlsxWorkbook
+	err := decodeZipXML(files, "xl/workbook.xml", &workbook)
+	if err != nil {
+		return "", err
+	}
+	if len(workbook.Sheets) == 0 {
+		return "", fmt.Errorf("workbook has no sheets")
+	}
+
+	var rels xlsxRelationships
+	err = decodeZipXML(files, "xl/_rels/workbook.xml.rels", &rels)
+	if err == nil {
+		for _, rel := range rels.Relationships {
+			if rel.ID != workbook.Sheets[0].RID {
+				continue
+			}
+			if strings.HasPrefix(rel.Target, "/") {
+				return strings.TrimPrefix(rel.Target, "/"), nil
+			}
+			return path.Join("xl", rel.Target), nil
+		}
+	}
+	return "xl/worksheets/sheet1.xml", nil
+}
+
+// maxXLSXEntrySize is the most a single file in a workbook may decompress to. A small upload can otherwise
+// decompress to gigabytes.
+const maxXLSXEntrySize = 50 * 1000 * 1000
+
+func decodeZipXML(files map[string]*zip.File, name string, v interface{}) error {
+	f, ok := files[name]
+	if !ok {
+		return fmt.Errorf("%s is missing", name)
+	}
+	if f.UncompressedSize64 > maxXLSXEntrySize {
+		return fmt.Errorf("%s is too big", name)
+	}
+
+	r, err := f.Open()
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+
+	// The size in the header can't be trusted, so don't read more than the limit either way
+	data, err := ioutil.ReadAll(io.LimitReader(r, maxXLSXEntrySize+1))
+	if err != nil {
+		return err
+	}
+	if len(data) > maxXLSXEntrySize {
+		return fmt.Errorf("%s is too big", name)
+	}
+	return xml.Unmarshal(data, v)
+}
+
+// columnIndex turns a cell reference like "C12" into a zero-based column index
+func columnIndex(ref string) int {
+	col := 0
+	for _, c := range ref {
+		if c < 'A' || c > 'Z' {
+			break
+		}
+		col = col*26 + int(c-'A'+1)
+		if col > MaxColumns {
+			// Don't let long references overflow
+			break
+		}
+	}
+	return col - 1
+}
+
+var xlsxSkeleton = map[string]string{
+	"[Content_Types].xml": `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">
+<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>
+<Default Extension="xml" ContentType="application/xml"/>
+<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>
+<Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>
+</Types>`,
+	"_rels/.rels": `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">
+<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>
+</Relationships>`,
+	"xl/workbook.xml": `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">
+<sheets><sheet name="Sheet1" sheetId="1" r:id="rId1"/></sheets>
+</workbook>`,
+	"xl/_rels/workbook.xml.rels": `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">
+<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/>
+</Relationships>`,
+}
+
+var reXLSXNumber = regexp.MustCompile(`^-?\d+(\.\d+)?$`)
+
+var xlsxSkeletonOrder = []string{"[Content_Types].xml", "_rels/.rels", "xl/workbook.xml", "xl/_rels/workbook.xml.rels"}
+
+// writeXLSX writes the rows into the first worksheet of a new workbook. Cells that are numbers are stored as
+// numbers so that spreadsheet programs can add them up.
+func writeXLSX(w io.Writer, rows [][]string) error {
+	z := zip.NewWriter(w)
+	for _, name := range xlsxSkeletonOrder {
+		f, err := z.Create(name)
+		if err != nil {
+			return err
+		}
+		_, err = io.WriteString(f, xlsxSkeleton[name])
+		if err != nil {
+			return err
+		}
+	}
+
+	var sheet bytes.Buffer
+	sheet.WriteString(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` + "\n")
+	sheet.WriteString(`<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`)
+	for i, row := range rows {
+		fmt.Fprintf(&sheet, `<row r="%d">`, i+1)
+		for j, cell := range row {
+			ref := fmt.Sprintf("%s%d", columnName(j), i+1)
+			if reXLSXNumber.MatchString(cell) {
+				fmt.Fprintf(&sheet, `<c r="%s"><v>%s</v></c>`, ref, cell)
+				continue
+			}
+			fmt.Fprintf(&sheet, `<c r="%s" t="inlineStr"><is><t>`, ref)
+			xml.EscapeText(&sheet, []byte(cell))
+			sheet.WriteString(`</t></is></c>`)
+		}
+		sheet.WriteString(`</row>`)
+	}
+	sheet.WriteString(`</sheetData></worksheet>`)
+
+	f, err := z.Create("xl/worksheets/sheet1.xml")
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(sheet.Bytes())
+	if err != nil {
+		return err
+	}
+	return z.Close()
+}
+
+// columnName turns a zero-based column index into a column name like "C"
+func columnName(col int) string {
+	name := ""
+	for col++; col > 0; col = (col - 1) / 26 {
+		name = string(rune('A'+(col-1)%26)) + name
+	}
+	return name
+}
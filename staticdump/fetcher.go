@@ -4,6 +4,7 @@ import (
 	"log"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 	"sync"
 	"time"
@@ -172,3 +173,22 @@ func (f *StaticFetcher) loadTypes(staticCacheFile string, staticDumpURL string)
 	log.Println("Finished typedb fetch")
 	return nil
 }
+
+// TypeDBPaths returns the paths of all type databases in dbPath, newest first
+func TypeDBPaths(dbPath string) ([]string, error) {
+	matches, err := filepath.Glob(filepath.Join(dbPath, "types-*"))
+	if err != nil {
+		return nil, err
+	}
+
+	paths := make([]string, 0, len(matches))
+	for _, match := range matches {
+		// Skip the downloaded zip files, search indexes and anything else that lives next to the databases
+		if filepath.Ext(match) != "" {
+			continue
+		}
+		paths = append(paths, match)
+	}
+	sort.Sort(sort.Reverse(sort.StringSlice(paths)))
+	return paths, nil
+}
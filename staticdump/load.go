@@ -137,6 +137,11 @@ type Type struct {
 	MarketGroupID int64 `yaml:"marketGroupID"`
 	Name          struct {
 		En string
+		De string
+		Fr string
+		Ru string
+		Ja string
+		Zh string
 	}
 	Published bool
 	Volume    float64
@@ -166,6 +171,37 @@ type MaterialType struct {
 	TypeID 		int64 `yaml:"typeID"`
 }
 
+type PlanetSchematic struct {
+	SchematicID   int64  `yaml:"schematicID"`
+	SchematicName string `yaml:"schematicName"`
+	CycleTime     int64  `yaml:"cycleTime"`
+}
+
+type PlanetSchematicType struct {
+	SchematicID int64 `yaml:"schematicID"`
+	TypeID      int64 `yaml:"typeID"`
+	Quantity    int64
+	IsInput     yamlBool `yaml:"isInput"`
+}
+
+// yamlBool is a boolean that is written as true/false or as 1/0
+type yamlBool bool
+
+func (b *yamlBool) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var i int64
+	if err := unmarshal(&i); err == nil {
+		*b = i != 0
+		return nil
+	}
+
+	var v bool
+	if err := unmarshal(&v); err != nil {
+		return err
+	}
+	*b = yamlBool(v)
+	return nil
+}
+
 func loadtypes(staticDataPath string) ([]typedb.EveType, error) {
 	r, err := zip.OpenReader(staticDataPath)
 	if err != nil {
@@ -219,7 +255,12 @@ func loadtypes(staticDataPath string) ([]typedb.EveType, error) {
 		if !ok {
 			materials = []typedb.Component{}
 		}
-		materialsByType[material.TypeID] = append(materials,typedb.Component{material.Quantity, material.MaterialID})
+		materialsByType[material.TypeID] = append(materials,typedb.Component{Quantity: material.Quantity, TypeID: material.MaterialID})
+	}
+
+	schematicsByInputType, err := loadPlanetSchematics(r)
+	if err != nil {
+		return nil, err
 	}
 
 	types := make([]typedb.EveType, 0)
@@ -245,6 +286,8 @@ func loadtypes(staticDataPath string) ([]typedb.EveType, error) {
 			Components:        resolveComponents(blueprintsByProductType, typeID),
 			BaseComponents:    flattenComponents(resolveBaseComponents(blueprintsByProductType, typeID, 1, 5)),
 			Materials:		   materialsByType[typeID],
+			LocalizedNames:    localizedNames(t),
+			Schematics:        schematicsByInputType[typeID],
 		}
 		types = append(types, eveType)
 	}
@@ -252,6 +295,84 @@ func loadtypes(staticDataPath string) ([]typedb.EveType, error) {
 	return types, nil
 }
 
+// localizedNames returns the names of a type in the other client languages, leaving out the ones that are
+// missing or the same as the English name
+func localizedNames(t Type) map[string]string {
+	names := make(map[string]string)
+	for lang, name := range map[string]string{"de": t.Name.De, "fr": t.Name.Fr, "ru": t.Name.Ru, "ja": t.Name.Ja, "zh": t.Name.Zh} {
+		if name != "" && name != t.Name.En {
+			names[lang] = name
+		}
+	}
+	if len(names) == 0 {
+		return nil
+	}
+	return names
+}
+
+// loadPlanetSchematics returns the planet schematics that use each type as an input. The schematics are only used
+// for the processing options of PI appraisals, so a static dump without them still loads, just without schematics.
+func loadPlanetSchematics(r *zip.ReadCloser) (map[int64][]typedb.Schematic, error) {
+	for _, filename := range []string{"sde/bsd/planetSchematics.yaml", "sde/bsd/planetSchematicsTypeMap.yaml"} {
+		if _, err := findZipFile(r.File, filename); err != nil {
+			log.Printf("WARNING: %s, PI appraisals won't have processing options", err)
+			return nil, nil
+		}
+	}
+
+	var allSchematics []PlanetSchematic
+	err := loadDataFromZipFile(r, "sde/bsd/planetSchematics.yaml", &allSchematics)
+	if err != nil {
+		return nil, err
+	}
+
+	var allSchematicTypes []PlanetSchematicType
+	err = loadDataFromZipFile(r, "sde/bsd/planetSchematicsTypeMap.yaml", &allSchematicTypes)
+	if err != nil {
+		return nil, err
+	}
+	log.Printf("Loaded %d planet schematics", len(allSchematics))
+
+	return resolveSchematics(allSchematics, allSchematicTypes), nil
+}
+
+// resolveSchematics returns the planet schematics that use each type as an input
+func resolveSchematics(schematics []PlanetSchematic, schematicTypes []PlanetSchematicType) map[int64][]typedb.Schematic {
+	schematicsByID := make(map[int64]*typedb.Schematic)
+	var ids []int64
+	for _, schematic := range schematics {
+		schematicsByID[schematic.SchematicID] = &typedb.Schematic{
+			ID:        schematic.SchematicID,
+			Name:      schematic.SchematicName,
+			CycleTime: schematic.CycleTime,
+		}
+		ids = append(ids, schematic.SchematicID)
+	}
+
+	for _, schematicType := range schematicTypes {
+		schematic, ok := schematicsByID[schematicType.SchematicID]
+		if !ok {
+			continue
+		}
+
+		component := typedb.Component{Quantity: schematicType.Quantity, TypeID: schematicType.TypeID}
+		if schematicType.IsInput {
+			schematic.Inputs = append(schematic.Inputs, component)
+		} else {
+			schematic.Output = component
+		}
+	}
+
+	schematicsByInputType := make(map[int64][]typedb.Schematic)
+	for _, id := range ids {
+		schematic := schematicsByID[id]
+		for _, input := range schematic.Inputs {
+			schematicsByInputType[input.TypeID] = append(schematicsByInputType[input.TypeID], *schematic)
+		}
+	}
+	return schematicsByInputType
+}
+
 func resolveBlueprintProducts(blueprintsByProductType map[int64][]Blueprint, typeID int64) []typedb.Component {
 	blueprints, ok := blueprintsByProductType[typeID]
 	if !ok || len(blueprints) == 0 {
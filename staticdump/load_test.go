@@ -0,0 +1,93 @@
+package staticdump
+
+import (
+	"archive/zip"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/evepraisal/go-evepraisal/typedb"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v2"
+)
+
+func TestYAMLBool(rt *testing.T) {
+	cases := []struct {
+		in       string
+		expected yamlBool
+	}{
+		{"isInput: 1", true},
+		{"isInput: 0", false},
+		{"isInput: true", true},
+		{"isInput: false", false},
+	}
+
+	for _, c := range cases {
+		rt.Run(c.in, func(t *testing.T) {
+			var v PlanetSchematicType
+			assert.NoError(t, yaml.Unmarshal([]byte(c.in), &v))
+			assert.Equal(t, c.expected, v.IsInput)
+		})
+	}
+
+	rt.Run("isInput: maybe", func(t *testing.T) {
+		var v PlanetSchematicType
+		assert.Error(t, yaml.Unmarshal([]byte("isInput: maybe"), &v))
+	})
+}
+
+func TestResolveSchematics(t *testing.T) {
+	schematics := []PlanetSchematic{
+		{SchematicID: 121, SchematicName: "Bacteria", CycleTime: 1800},
+		{SchematicID: 66, SchematicName: "Fertilizer", CycleTime: 3600},
+	}
+	schematicTypes := []PlanetSchematicType{
+		{SchematicID: 121, TypeID: 2073, Quantity: 3000, IsInput: true},
+		{SchematicID: 121, TypeID: 2393, Quantity: 20},
+		{SchematicID: 66, TypeID: 2393, Quantity: 40, IsInput: true},
+		{SchematicID: 66, TypeID: 2397, Quantity: 40, IsInput: true},
+		{SchematicID: 66, TypeID: 3693, Quantity: 5},
+		// Rows for unknown schematics are left out
+		{SchematicID: 999, TypeID: 2073, Quantity: 1, IsInput: true},
+	}
+
+	fertilizer := typedb.Schematic{
+		ID: 66, Name: "Fertilizer", CycleTime: 3600,
+		Inputs: []typedb.Component{{TypeID: 2393, Quantity: 40}, {TypeID: 2397, Quantity: 40}},
+		Output: typedb.Component{TypeID: 3693, Quantity: 5},
+	}
+	assert.Equal(t, map[int64][]typedb.Schematic{
+		2073: {{
+			ID: 121, Name: "Bacteria", CycleTime: 1800,
+			Inputs: []typedb.Component{{TypeID: 2073, Quantity: 3000}},
+			Output: typedb.Component{TypeID: 2393, Quantity: 20},
+		}},
+		2393: {fertilizer},
+		2397: {fertilizer},
+	}, resolveSchematics(schematics, schematicTypes))
+}
+
+func TestLoadPlanetSchematicsMissing(t *testing.T) {
+	dir, err := ioutil.TempDir("", "staticdump")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "sde.zip")
+	f, err := os.Create(path)
+	assert.NoError(t, err)
+	z := zip.NewWriter(f)
+	w, err := z.Create("sde/bsd/planetSchematics.yaml")
+	assert.NoError(t, err)
+	w.Write([]byte("- schematicID: 66\n  schematicName: Fertilizer\n  cycleTime: 3600\n"))
+	assert.NoError(t, z.Close())
+	assert.NoError(t, f.Close())
+
+	r, err := zip.OpenReader(path)
+	assert.NoError(t, err)
+	defer r.Close()
+
+	schematics, err := loadPlanetSchematics(r)
+	assert.NoError(t, err)
+	assert.Nil(t, schematics)
+}
@@ -0,0 +1,148 @@
+package evepraisal
+
+import (
+	"sort"
+
+	"github.com/evepraisal/go-evepraisal/parsers"
+	"github.com/spf13/viper"
+)
+
+// SurveySettings describe the ship that mines the rocks of a survey scan. Yield is how many m3 of ore the ship
+// mines per cycle, CycleTime is in seconds and RefineRate is the percentage of the minerals that refining yields.
+type SurveySettings struct {
+	Ship       string  `json:"ship,omitempty"`
+	Yield      float64 `json:"yield"`
+	CycleTime  float64 `json:"cycle_time"`
+	RefineRate float64 `json:"refine_rate"`
+}
+
+// DefaultSurveySettings returns the settings from the survey-* config and the refine rate of the buyback program
+func DefaultSurveySettings() SurveySettings {
+	return SurveySettings{
+		Ship:       viper.GetString("survey-ship"),
+		Yield:      viper.GetFloat64("survey-yield"),
+		CycleTime:  viper.GetFloat64("survey-cycle-time"),
+		RefineRate: viper.GetFloat64("buyback-refine-rate"),
+	}
+}
+
+// SurveyEstimate is what the rocks of a survey scan are worth when they are mined and refined. Rocks are ranked by
+// ISK per m3, which says which rocks are worth mining first.
+type SurveyEstimate struct {
+	Settings     SurveySettings `json:"settings"`
+	Rocks        []SurveyRock   `json:"rocks"`
+	Volume       float64        `json:"volume"`
+	Value        float64        `json:"value"`
+	Hours        float64        `json:"hours"`
+	ValuePerHour float64        `json:"value_per_hour"`
+}
+
+// SurveyRock is a single rock from the scan. MineralValue is what the minerals of a single unit of the ore are
+// worth when all of them are refined, everything else follows from it and the settings.
+type SurveyRock struct {
+	TypeID        int64   `json:"typeID"`
+	Name          string  `json:"name"`
+	Quantity      int64   `json:"quantity"`
+	Distance      string  `json:"distance"`
+	UnitVolume    float64 `json:"unit_volume"`
+	MineralValue  float64 `json:"mineral_value"`
+	Volume        float64 `json:"volume"`
+	Value         float64 `json:"value"`
+	ISKPerM3      float64 `json:"isk_per_m3"`
+	Cycles        float64 `json:"cycles"`
+	ValuePerCycle float64 `json:"value_per_cycle"`
+	ValuePerHour  float64 `json:"value_per_hour"`
+}
+
+// Estimate works out the values of the rocks for the given settings and ranks them
+func (estimate *SurveyEstimate) Estimate(settings SurveySettings) {
+	estimate.Settings = settings
+	estimate.Volume, estimate.Value, estimate.Hours, estimate.ValuePerHour = 0, 0, 0, 0
+
+	for i := range estimate.Rocks {
+		rock := &estimate.Rocks[i]
+		rock.Volume = float64(rock.Quantity) * rock.UnitVolume
+		rock.Value = float64(rock.Quantity) * rock.MineralValue * settings.RefineRate / 100
+		rock.ISKPerM3, rock.Cycles, rock.ValuePerCycle, rock.ValuePerHour = 0, 0, 0, 0
+		if rock.UnitVolume > 0 {
+			rock.ISKPerM3 = rock.MineralValue * settings.RefineRate / 100 / rock.UnitVolume
+		}
+		if settings.Yield > 0 {
+			rock.Cycles = rock.Volume / settings.Yield
+			rock.ValuePerCycle = settings.Yield * rock.ISKPerM3
+			if rock.Volume < settings.Yield {
+				rock.ValuePerCycle = rock.Value
+			}
+		}
+		if settings.CycleTime > 0 {
+			rock.ValuePerHour = settings.Yield * rock.ISKPerM3 * 3600 / settings.CycleTime
+		}
+
+		estimate.Volume += rock.Volume
+		estimate.Value += rock.Value
+		estimate.Hours += rock.Cycles * settings.CycleTime / 3600
+	}
+	if estimate.Hours > 0 {
+		estimate.ValuePerHour = estimate.Value / estimate.Hours
+	}
+
+	sort.SliceStable(estimate.Rocks, func(i, j int) bool {
+		return estimate.Rocks[i].ISKPerM3 > estimate.Rocks[j].ISKPerM3
+	})
+}
+
+// newSurveyEstimate returns the estimate for the survey scans in the parser result, or nil if there aren't any
+func (app *App) newSurveyEstimate(result parsers.ParserResult, market string) *SurveyEstimate {
+	scans := findSurveyScans(result)
+	if len(scans) == 0 {
+		return nil
+	}
+
+	estimate := &SurveyEstimate{}
+	mineralValues := make(map[int64]float64)
+	for _, scan := range scans {
+		for _, item := range scan.Items {
+			t, ok := app.TypeDB.GetType(item.Name)
+			if !ok {
+				continue
+			}
+
+			mineralValue, ok := mineralValues[t.ID]
+			if !ok && t.PortionSize > 0 {
+				for _, material := range t.Materials {
+					if mt, ok := app.TypeDB.GetTypeByID(material.TypeID); ok {
+						mineralValue += float64(material.Quantity) * app.typePrice(market, mt)
+					}
+				}
+				mineralValue /= float64(t.PortionSize)
+				mineralValues[t.ID] = mineralValue
+			}
+
+			estimate.Rocks = append(estimate.Rocks, SurveyRock{
+				TypeID:       t.ID,
+				Name:         t.Name,
+				Quantity:     item.Quantity,
+				Distance:     item.Distance,
+				UnitVolume:   t.Volume,
+				MineralValue: mineralValue,
+			})
+		}
+	}
+
+	estimate.Estimate(DefaultSurveySettings())
+	return estimate
+}
+
+func findSurveyScans(result parsers.ParserResult) []*parsers.SurveyScan {
+	switch r := result.(type) {
+	case *parsers.SurveyScan:
+		return []*parsers.SurveyScan{r}
+	case *parsers.MultiParserResult:
+		var scans []*parsers.SurveyScan
+		for _, subResult := range r.Results {
+			scans = append(scans, findSurveyScans(subResult)...)
+		}
+		return scans
+	}
+	return nil
+}
@@ -0,0 +1,147 @@
+package typedb
+
+import (
+	"log"
+	"sync"
+	"time"
+)
+
+// Archive keeps a number of recent type databases open so that appraisals can be resolved against
+// the static dump version they were created with
+type Archive struct {
+	keep       int
+	closeDelay time.Duration
+	dbs        []TypeDB
+	retiring   []retiringTypeDB
+
+	mu sync.RWMutex
+}
+
+// retiringTypeDB is a type database that has been dropped from the archive but is kept open for a while
+type retiringTypeDB struct {
+	db    TypeDB
+	timer *time.Timer
+}
+
+// NewArchive returns an archive that holds at most keep type databases. Databases that are dropped from
+// the archive are closed after closeDelay, so requests that are still using them can finish.
+func NewArchive(keep int, closeDelay time.Duration) *Archive {
+	if keep < 1 {
+		keep = 1
+	}
+	return &Archive{keep: keep, closeDelay: closeDelay, dbs: make([]TypeDB, 0, keep)}
+}
+
+// Add makes db the newest type database. An older database with the same version is replaced and the
+// oldest databases beyond the limit are closed once the close delay has passed.
+func (a *Archive) Add(db TypeDB) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	dbs := []TypeDB{db}
+	for _, old := range a.dbs {
+		if old.Version() == db.Version() || len(dbs) >= a.keep {
+			a.retire(old)
+			continue
+		}
+		dbs = append(dbs, old)
+	}
+	a.dbs = dbs
+}
+
+// AddOlder adds a type database behind all of the ones that are already in the archive. This is used to
+// load previous versions from disk. It returns false (and doesn't take ownership of db) if there is no room.
+func (a *Archive) AddOlder(db TypeDB) bool {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if len(a.dbs) >= a.keep {
+		return false
+	}
+	for _, old := range a.dbs {
+		if old.Version() == db.Version() {
+			return false
+		}
+	}
+	a.dbs = append(a.dbs, db)
+	return true
+}
+
+// Get returns the type database for the given version
+func (a *Archive) Get(version string) (TypeDB, bool) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	for _, db := range a.dbs {
+		if db.Version() == version {
+			return db, true
+		}
+	}
+	return nil, false
+}
+
+// Versions returns the versions in the archive, newest first
+func (a *Archive) Versions() []string {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	versions := make([]string, len(a.dbs))
+	for i, db := range a.dbs {
+		versions[i] = db.Version()
+	}
+	return versions
+}
+
+// Close closes every type database in the archive, including the ones that are waiting to be closed
+func (a *Archive) Close() error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	var err error
+	for _, db := range a.dbs {
+		closeErr := db.Close()
+		if closeErr != nil {
+			err = closeErr
+		}
+	}
+	a.dbs = nil
+
+	for _, r := range a.retiring {
+		// If the timer already fired, it closes the database itself
+		if r.timer.Stop() {
+			a.close(r.db)
+		}
+	}
+	a.retiring = nil
+	return err
+}
+
+// retire closes db after the close delay. The lock must be held.
+func (a *Archive) retire(db TypeDB) {
+	if a.closeDelay <= 0 {
+		a.close(db)
+		return
+	}
+
+	r := retiringTypeDB{db: db}
+	r.timer = time.AfterFunc(a.closeDelay, func() {
+		a.mu.Lock()
+		for i := range a.retiring {
+			if a.retiring[i].timer == r.timer {
+				a.retiring = append(a.retiring[:i], a.retiring[i+1:]...)
+				break
+			}
+		}
+		a.mu.Unlock()
+		a.close(db)
+	})
+	a.retiring = append(a.retiring, r)
+}
+
+func (a *Archive) close(db TypeDB) {
+	log.Printf("closing typedb %s", db.Version())
+	err := db.Close()
+	if err != nil {
+		log.Printf("WARNING: Problem closing typedb %s: %s", db.Version(), err)
+	}
+}
@@ -0,0 +1,102 @@
+package typedb
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeTypeDB struct {
+	version string
+
+	mu     sync.Mutex
+	closed bool
+}
+
+func (db *fakeTypeDB) GetType(typeName string) (EveType, bool) { return EveType{}, false }
+func (db *fakeTypeDB) HasType(typeName string) bool            { return false }
+func (db *fakeTypeDB) GetTypeByID(typeID int64) (EveType, bool) {
+	return EveType{}, false
+}
+func (db *fakeTypeDB) PutType(EveType) error     { return nil }
+func (db *fakeTypeDB) Search(s string) []EveType { return nil }
+func (db *fakeTypeDB) Version() string           { return db.version }
+func (db *fakeTypeDB) Delete() error             { return nil }
+
+func (db *fakeTypeDB) Close() error {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	db.closed = true
+	return nil
+}
+
+func (db *fakeTypeDB) isClosed() bool {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	return db.closed
+}
+
+func TestArchiveAdd(t *testing.T) {
+	a := NewArchive(2, 0)
+	v1, v2, v3 := &fakeTypeDB{version: "v1"}, &fakeTypeDB{version: "v2"}, &fakeTypeDB{version: "v3"}
+
+	a.Add(v1)
+	a.Add(v2)
+	assert.Equal(t, []string{"v2", "v1"}, a.Versions())
+
+	a.Add(v3)
+	assert.Equal(t, []string{"v3", "v2"}, a.Versions())
+	assert.True(t, v1.isClosed(), "the oldest typedb should be closed")
+	assert.False(t, v2.isClosed())
+
+	db, ok := a.Get("v2")
+	assert.True(t, ok)
+	assert.Equal(t, v2, db)
+	_, ok = a.Get("v1")
+	assert.False(t, ok)
+
+	// The same version again replaces the old one
+	v3again := &fakeTypeDB{version: "v3"}
+	a.Add(v3again)
+	assert.Equal(t, []string{"v3", "v2"}, a.Versions())
+	assert.True(t, v3.isClosed())
+	db, _ = a.Get("v3")
+	assert.Equal(t, v3again, db)
+
+	assert.NoError(t, a.Close())
+	assert.True(t, v2.isClosed())
+	assert.True(t, v3again.isClosed())
+}
+
+func TestArchiveAddOlder(t *testing.T) {
+	a := NewArchive(2, 0)
+	v1, v2, v3 := &fakeTypeDB{version: "v1"}, &fakeTypeDB{version: "v2"}, &fakeTypeDB{version: "v3"}
+
+	a.Add(v3)
+	assert.True(t, a.AddOlder(v2))
+	assert.False(t, a.AddOlder(v1), "there is no room left")
+	assert.False(t, a.AddOlder(&fakeTypeDB{version: "v3"}), "versions are unique")
+	assert.Equal(t, []string{"v3", "v2"}, a.Versions())
+	assert.False(t, v1.isClosed(), "rejected typedbs are not owned by the archive")
+}
+
+func TestArchiveCloseDelay(t *testing.T) {
+	a := NewArchive(1, 50*time.Millisecond)
+	v1, v2, v3 := &fakeTypeDB{version: "v1"}, &fakeTypeDB{version: "v2"}, &fakeTypeDB{version: "v3"}
+
+	a.Add(v1)
+	a.Add(v2)
+	assert.Equal(t, []string{"v2"}, a.Versions())
+	assert.False(t, v1.isClosed(), "dropped typedbs stay open for a while")
+
+	time.Sleep(200 * time.Millisecond)
+	assert.True(t, v1.isClosed())
+
+	// Closing the archive doesn't wait for the delay
+	a.Add(v3)
+	assert.NoError(t, a.Close())
+	assert.True(t, v2.isClosed())
+	assert.True(t, v3.isClosed())
+}
@@ -6,6 +6,7 @@ type TypeDB interface {
 	GetTypeByID(typeID int64) (EveType, bool)
 	PutType(EveType) error
 	Search(s string) []EveType
+	Version() string
 	Delete() error
 	Close() error
 }
@@ -24,6 +25,19 @@ type EveType struct {
 	Components        []Component `json:"components,omitempty"`
 	BaseComponents    []Component `json:"base_components,omitempty"`
 	Materials		  []Component `json:"materials,omitempty"`
+	LocalizedNames    map[string]string `json:"localized_names,omitempty"`
+	// Planetary interaction schematics that use the type as an input
+	Schematics []Schematic `json:"schematics,omitempty"`
+}
+
+// Schematic is a planetary interaction schematic which turns the inputs into the output once every cycle. The
+// cycle time is in seconds.
+type Schematic struct {
+	ID        int64       `json:"id"`
+	Name      string      `json:"name"`
+	CycleTime int64       `json:"cycle_time"`
+	Inputs    []Component `json:"inputs"`
+	Output    Component   `json:"output"`
 }
 
 type Component struct {
@@ -0,0 +1,224 @@
+package evepraisal
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/evepraisal/go-evepraisal/parsers"
+)
+
+// WalletAnalytics summarizes the wallet journal and market transactions of an appraisal made from a wallet
+type WalletAnalytics struct {
+	Transactions []AppraisalItem        `json:"transactions,omitempty"`
+	Types        []WalletTypeSummary    `json:"types"`
+	Stations     []WalletStationSummary `json:"stations"`
+	Journal      []WalletJournalSummary `json:"journal,omitempty"`
+	Totals       WalletTotals           `json:"totals"`
+}
+
+// WalletTypeSummary is what was bought and sold of a single type. RealizedProfit is what the sold units brought
+// in over what they cost, using the average buy price, so only units that were both bought and sold count.
+// MarketValue is what the units that were bought and not sold are worth at current prices.
+type WalletTypeSummary struct {
+	TypeID           int64   `json:"typeID"`
+	Name             string  `json:"name"`
+	Bought           int64   `json:"bought"`
+	Sold             int64   `json:"sold"`
+	BuyCost          float64 `json:"buy_cost"`
+	SellProceeds     float64 `json:"sell_proceeds"`
+	AverageBuyPrice  float64 `json:"average_buy_price"`
+	AverageSellPrice float64 `json:"average_sell_price"`
+	MarketPrice      float64 `json:"market_price"`
+	RealizedProfit   float64 `json:"realized_profit"`
+	MarketValue      float64 `json:"market_value"`
+}
+
+// WalletStationSummary is the number of units and the ISK that changed hands at a station
+type WalletStationSummary struct {
+	Location string  `json:"location"`
+	Quantity int64   `json:"quantity"`
+	Bought   float64 `json:"bought"`
+	Sold     float64 `json:"sold"`
+}
+
+// WalletJournalSummary adds up the journal entries of one kind, like "Market Escrow" or "Bounty Prizes"
+type WalletJournalSummary struct {
+	TransactionType string  `json:"transaction_type"`
+	Entries         int     `json:"entries"`
+	Amount          float64 `json:"amount"`
+}
+
+type WalletTotals struct {
+	BuyCost        float64 `json:"buy_cost"`
+	SellProceeds   float64 `json:"sell_proceeds"`
+	RealizedProfit float64 `json:"realized_profit"`
+	MarketValue    float64 `json:"market_value"`
+}
+
+// IsTransaction returns true if the item came from an itemized wallet transaction
+func (i AppraisalItem) IsTransaction() bool {
+	return i.Extra.Transaction != ""
+}
+
+// TransactionTotal is the ISK that was paid or received for the item
+func (i AppraisalItem) TransactionTotal() float64 {
+	return float64(i.Quantity) * i.Extra.TransactionPrice
+}
+
+// TransactionPriceDifference is how far the current market price is from the price of the transaction, in percent
+func (i AppraisalItem) TransactionPriceDifference() float64 {
+	if i.Extra.TransactionPrice == 0 {
+		return 0
+	}
+	return (i.SingleRepresentativePrice() - i.Extra.TransactionPrice) / i.Extra.TransactionPrice * 100
+}
+
+// newWalletAnalytics returns the analytics for the wallets in the parser result, or nil if there aren't any. The
+// items are the priced appraisal items, which the itemized transactions get their type and prices from. Every
+// transaction is kept on its own since the appraisal items combine all transactions of a type.
+func newWalletAnalytics(result parsers.ParserResult, items []AppraisalItem) *WalletAnalytics {
+	wallets := findWallets(result)
+	if len(wallets) == 0 {
+		return nil
+	}
+
+	priced := make(map[string]AppraisalItem)
+	for _, item := range items {
+		priced[strings.ToUpper(item.Name)] = item
+	}
+
+	analytics := &WalletAnalytics{}
+	for _, wallet := range wallets {
+		for _, transaction := range wallet.ItemizedTransactions {
+			analytics.Transactions = append(analytics.Transactions, walletTransactionItem(transaction, priced))
+		}
+	}
+
+	types := make(map[string]*WalletTypeSummary)
+	stations := make(map[string]*WalletStationSummary)
+	for _, item := range analytics.Transactions {
+		summary, ok := types[item.DisplayName()]
+		if !ok {
+			summary = &WalletTypeSummary{TypeID: item.TypeID, Name: item.DisplayName()}
+			types[item.DisplayName()] = summary
+		}
+		summary.MarketPrice = item.SingleRepresentativePrice()
+
+		station, ok := stations[item.Extra.Location]
+		if !ok {
+			station = &WalletStationSummary{Location: item.Extra.Location}
+			stations[item.Extra.Location] = station
+		}
+		station.Quantity += item.Quantity
+
+		if item.Extra.Transaction == "buy" {
+			summary.Bought += item.Quantity
+			summary.BuyCost += item.TransactionTotal()
+			station.Bought += item.TransactionTotal()
+		} else {
+			summary.Sold += item.Quantity
+			summary.SellProceeds += item.TransactionTotal()
+			station.Sold += item.TransactionTotal()
+		}
+	}
+
+	for _, summary := range types {
+		if summary.Bought > 0 {
+			summary.AverageBuyPrice = summary.BuyCost / float64(summary.Bought)
+		}
+		if summary.Sold > 0 {
+			summary.AverageSellPrice = summary.SellProceeds / float64(summary.Sold)
+		}
+
+		matched := summary.Sold
+		if summary.Bought < matched {
+			matched = summary.Bought
+		}
+		summary.RealizedProfit = float64(matched) * (summary.AverageSellPrice - summary.AverageBuyPrice)
+		if summary.Bought > summary.Sold {
+			summary.MarketValue = float64(summary.Bought-summary.Sold) * summary.MarketPrice
+		}
+
+		analytics.Totals.BuyCost += summary.BuyCost
+		analytics.Totals.SellProceeds += summary.SellProceeds
+		analytics.Totals.RealizedProfit += summary.RealizedProfit
+		analytics.Totals.MarketValue += summary.MarketValue
+		analytics.Types = append(analytics.Types, *summary)
+	}
+	sort.Slice(analytics.Types, func(i, j int) bool {
+		return analytics.Types[i].Name < analytics.Types[j].Name
+	})
+
+	for _, station := range stations {
+		analytics.Stations = append(analytics.Stations, *station)
+	}
+	sort.Slice(analytics.Stations, func(i, j int) bool {
+		return analytics.Stations[i].Bought+analytics.Stations[i].Sold > analytics.Stations[j].Bought+analytics.Stations[j].Sold
+	})
+
+	journal := make(map[string]*WalletJournalSummary)
+	for _, wallet := range wallets {
+		for _, transaction := range wallet.Transactions {
+			summary, ok := journal[transaction.TransactionType]
+			if !ok {
+				summary = &WalletJournalSummary{TransactionType: transaction.TransactionType}
+				journal[transaction.TransactionType] = summary
+			}
+			summary.Entries++
+			summary.Amount += iskAmount(transaction.Amount)
+		}
+	}
+	for _, summary := range journal {
+		analytics.Journal = append(analytics.Journal, *summary)
+	}
+	sort.Slice(analytics.Journal, func(i, j int) bool {
+		return analytics.Journal[i].TransactionType < analytics.Journal[j].TransactionType
+	})
+
+	return analytics
+}
+
+// walletTransactionItem turns an itemized transaction into an item with the type and prices of the appraisal item
+// with the same name
+func walletTransactionItem(transaction parsers.WalletItemizedTransaction, priced map[string]AppraisalItem) AppraisalItem {
+	name := strings.Trim(transaction.Name, " \t")
+	p := priced[strings.ToUpper(name)]
+	item := AppraisalItem{
+		Name:       name,
+		TypeID:     p.TypeID,
+		TypeName:   p.TypeName,
+		TypeVolume: p.TypeVolume,
+		Quantity:   transaction.Quantity,
+		Prices:     p.Prices,
+	}
+	item.Extra.Transaction = "sell"
+	if strings.HasPrefix(transaction.Credit, "-") {
+		item.Extra.Transaction = "buy"
+	}
+	item.Extra.TransactionPrice = iskAmount(transaction.Price)
+	item.Extra.Client = transaction.Client
+	item.Extra.Location = transaction.Location
+	return item
+}
+
+func findWallets(result parsers.ParserResult) []*parsers.Wallet {
+	switch r := result.(type) {
+	case *parsers.Wallet:
+		return []*parsers.Wallet{r}
+	case *parsers.MultiParserResult:
+		var wallets []*parsers.Wallet
+		for _, subResult := range r.Results {
+			wallets = append(wallets, findWallets(subResult)...)
+		}
+		return wallets
+	}
+	return nil
+}
+
+// iskAmount parses amounts from the wallet like "-200,000.00 ISK"
+func iskAmount(s string) float64 {
+	s = strings.TrimSpace(s)
+	s = strings.TrimSuffix(s, "ISK")
+	s = strings.TrimSuffix(s, "AUR")
+	return parsers.ToDecimal(strings.TrimSpace(s))
+}
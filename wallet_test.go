@@ -0,0 +1,167 @@
+package evepraisal
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/evepraisal/go-evepraisal/parsers"
+	"github.com/stretchr/testify/assert"
+)
+
+// itemizedTransaction is a market transaction from the wallet. Buying shows up with a negative credit.
+func itemizedTransaction(name string, transaction string, quantity int64, price float64, location string) parsers.WalletItemizedTransaction {
+	credit := fmt.Sprintf("%.2f ISK", price*float64(quantity))
+	if transaction == "buy" {
+		credit = "-" + credit
+	}
+	return parsers.WalletItemizedTransaction{
+		Name:     name,
+		Price:    fmt.Sprintf("%.2f ISK", price),
+		Quantity: quantity,
+		Credit:   credit,
+		Client:   "Some Trader",
+		Location: location,
+	}
+}
+
+func pricedItem(name string, marketPrice float64) AppraisalItem {
+	item := AppraisalItem{Name: name, TypeName: name}
+	item.Prices.Sell.Min = marketPrice
+	item.Prices.Buy.Max = marketPrice
+	return item
+}
+
+func TestWalletAnalytics(rt *testing.T) {
+	cases := []struct {
+		description string
+		result      parsers.ParserResult
+		items       []AppraisalItem
+		expected    *WalletAnalytics
+	}{
+		{
+			"not a wallet",
+			&parsers.Listing{},
+			[]AppraisalItem{pricedItem("Tritanium", 6)},
+			nil,
+		}, {
+			"profit is only realized on units that were bought and sold",
+			&parsers.Wallet{ItemizedTransactions: []parsers.WalletItemizedTransaction{
+				itemizedTransaction("Tritanium", "buy", 100, 5, "Jita"),
+				itemizedTransaction("Tritanium", "buy", 100, 7, "Jita"),
+				itemizedTransaction("Tritanium", "sell", 50, 10, "Amarr"),
+			}},
+			[]AppraisalItem{pricedItem("Tritanium", 6), pricedItem("Pyerite", 10)},
+			&WalletAnalytics{
+				Types: []WalletTypeSummary{{
+					Name: "Tritanium", Bought: 200, Sold: 50, BuyCost: 1200, SellProceeds: 500,
+					AverageBuyPrice: 6, AverageSellPrice: 10, MarketPrice: 6, RealizedProfit: 200, MarketValue: 900,
+				}},
+				Stations: []WalletStationSummary{
+					{Location: "Jita", Quantity: 200, Bought: 1200},
+					{Location: "Amarr", Quantity: 50, Sold: 500},
+				},
+				Totals: WalletTotals{BuyCost: 1200, SellProceeds: 500, RealizedProfit: 200, MarketValue: 900},
+			},
+		}, {
+			"selling more than was bought has no market value left",
+			&parsers.Wallet{ItemizedTransactions: []parsers.WalletItemizedTransaction{
+				itemizedTransaction("Rifter", "buy", 1, 400000, "Jita"),
+				itemizedTransaction("Rifter", "sell", 3, 450000, "Jita"),
+				itemizedTransaction("Mexallon", "sell", 10, 50, "Dodixie"),
+			}},
+			[]AppraisalItem{pricedItem("Rifter", 500000), pricedItem("Mexallon", 40)},
+			&WalletAnalytics{
+				Types: []WalletTypeSummary{
+					{Name: "Mexallon", Sold: 10, SellProceeds: 500, AverageSellPrice: 50, MarketPrice: 40},
+					{
+						Name: "Rifter", Bought: 1, Sold: 3, BuyCost: 400000, SellProceeds: 1350000,
+						AverageBuyPrice: 400000, AverageSellPrice: 450000, MarketPrice: 500000, RealizedProfit: 50000,
+					},
+				},
+				Stations: []WalletStationSummary{
+					{Location: "Jita", Quantity: 4, Bought: 400000, Sold: 1350000},
+					{Location: "Dodixie", Quantity: 10, Sold: 500},
+				},
+				Totals: WalletTotals{BuyCost: 400000, SellProceeds: 1350500, RealizedProfit: 50000},
+			},
+		}, {
+			"journal entries are added up by kind",
+			&parsers.MultiParserResult{Results: []parsers.ParserResult{
+				&parsers.Wallet{Transactions: []parsers.WalletTransaction{
+					{TransactionType: "Market Escrow", Amount: "-200,000.00 ISK"},
+					{TransactionType: "Bounty Prizes", Amount: "1,500,000.50 ISK"},
+				}},
+				&parsers.Wallet{Transactions: []parsers.WalletTransaction{
+					{TransactionType: "Market Escrow", Amount: "-50,000 ISK"},
+				}},
+			}},
+			nil,
+			&WalletAnalytics{
+				Journal: []WalletJournalSummary{
+					{TransactionType: "Bounty Prizes", Entries: 1, Amount: 1500000.5},
+					{TransactionType: "Market Escrow", Entries: 2, Amount: -250000},
+				},
+			},
+		},
+	}
+
+	for _, c := range cases {
+		rt.Run(c.description, func(t *testing.T) {
+			analytics := newWalletAnalytics(c.result, c.items)
+			if analytics != nil {
+				// The transactions themselves are checked in TestWalletTransactions
+				analytics.Transactions = nil
+			}
+			assert.Equal(t, c.expected, analytics)
+		})
+	}
+}
+
+func TestWalletTransactions(t *testing.T) {
+	result := &parsers.Wallet{ItemizedTransactions: []parsers.WalletItemizedTransaction{
+		itemizedTransaction(" Tritanium", "buy", 100, 5, "Jita"),
+		itemizedTransaction("Tritanium", "sell", 50, 10, "Amarr"),
+		itemizedTransaction("Unknown Thing", "sell", 1, 10, "Amarr"),
+	}}
+	analytics := newWalletAnalytics(result, []AppraisalItem{pricedItem("Tritanium", 6)})
+	if !assert.Len(t, analytics.Transactions, 3) {
+		return
+	}
+
+	bought, sold, unknown := analytics.Transactions[0], analytics.Transactions[1], analytics.Transactions[2]
+	assert.Equal(t, "Tritanium", bought.Name)
+	assert.Equal(t, "buy", bought.Extra.Transaction)
+	assert.Equal(t, 5.0, bought.Extra.TransactionPrice)
+	assert.Equal(t, 500.0, bought.TransactionTotal())
+	assert.Equal(t, "Some Trader", bought.Extra.Client)
+	assert.Equal(t, "Jita", bought.Extra.Location)
+	assert.Equal(t, 6.0, bought.Prices.Sell.Min)
+	assert.Equal(t, 20.0, bought.TransactionPriceDifference())
+
+	assert.Equal(t, "sell", sold.Extra.Transaction)
+	assert.Equal(t, int64(50), sold.Quantity)
+	assert.Equal(t, "Amarr", sold.Extra.Location)
+
+	assert.Equal(t, "Unknown Thing", unknown.DisplayName())
+	assert.Equal(t, 0.0, unknown.Prices.Sell.Min)
+}
+
+func TestISKAmount(rt *testing.T) {
+	cases := []struct {
+		in       string
+		expected float64
+	}{
+		{"-200,000.00 ISK", -200000},
+		{"1,234,567.89 ISK", 1234567.89},
+		{" 15 ISK ", 15},
+		{"500 AUR", 500},
+		{"-1.500,25 ISK", -1500.25},
+		{"", 0},
+	}
+
+	for _, c := range cases {
+		rt.Run(c.in, func(t *testing.T) {
+			assert.Equal(t, c.expected, iskAmount(c.in))
+		})
+	}
+}
@@ -0,0 +1,37 @@
+package web
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+
+	"github.com/evepraisal/go-evepraisal"
+)
+
+// export writes the appraisals with the exporter that was asked for with the suffix of the URL. It returns false
+// if the request isn't for an export. It's used by the appraisal page and the user history; there is no compare
+// view yet, but one would only have to pass its appraisals here.
+func (ctx *Context) export(w http.ResponseWriter, r *http.Request, filename string, appraisals []evepraisal.Appraisal) bool {
+	exporter, ok := evepraisal.FindExporter(r.Header.Get("format"))
+	if !ok {
+		return false
+	}
+
+	// Exports are written to a buffer first so that errors can still be reported properly
+	var buffer bytes.Buffer
+	err := exporter.Export(&buffer, appraisals, ctx.App.TypeDB)
+	if err == evepraisal.ErrNotExportable {
+		ctx.renderErrorPage(r, w, http.StatusNotFound, "Not Found", err.Error())
+		return true
+	} else if err != nil {
+		ctx.renderServerError(r, w, err)
+		return true
+	}
+
+	w.Header().Add("Content-Type", exporter.ContentType)
+	if exporter.Attachment {
+		w.Header().Add("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename+"."+exporter.Suffix))
+	}
+	w.Write(buffer.Bytes())
+	return true
+}
@@ -0,0 +1,54 @@
+package web
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/evepraisal/go-evepraisal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExport(t *testing.T) {
+	ctx := &Context{App: &evepraisal.App{}}
+	appraisal := evepraisal.Appraisal{ID: "abc"}
+	rifter := evepraisal.AppraisalItem{Name: "Rifter", TypeID: 587, Quantity: 1}
+	autocannon := evepraisal.AppraisalItem{Name: "200mm AutoCannon II", TypeID: 2889, Quantity: 3}
+	ammo := evepraisal.AppraisalItem{Name: "EMP S", TypeID: 185, Quantity: 200}
+	autocannon.Prices.Sell.Min = 1000.5
+	appraisal.Original.Items = []evepraisal.AppraisalItem{rifter, autocannon, ammo}
+	appraisal.Groups = []evepraisal.ItemGroup{
+		{Name: "Fleet Rifter", Ship: "Rifter", Items: []evepraisal.AppraisalItem{rifter, autocannon, ammo}},
+	}
+
+	export := func(format string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest("GET", "/a/abc", nil)
+		r.Header.Set("format", format)
+		w := httptest.NewRecorder()
+		assert.True(t, ctx.export(w, r, "appraisal-abc", []evepraisal.Appraisal{appraisal}))
+		return w
+	}
+
+	w := export("csv")
+	assert.Equal(t, `attachment; filename="appraisal-abc.csv"`, w.Header().Get("Content-Disposition"))
+	assert.Equal(t, `Appraisal,Type ID,Name,Quantity,Volume,Buy,Buy Total,Sell,Sell Total,Adjustment,Buyback Buy Total,Buyback Items
+abc,587,Rifter,1,0,0.00,0.00,0.00,0.00,100,0.00,
+abc,2889,200mm AutoCannon II,3,0,0.00,0.00,1000.50,3001.50,100,0.00,
+abc,185,EMP S,200,0,0.00,0.00,0.00,0.00,100,0.00,
+`, w.Body.String())
+
+	// Without a type database, only items with a quantity of one count as fitted
+	w = export("eft")
+	assert.Equal(t, "", w.Header().Get("Content-Disposition"))
+	assert.Equal(t, `[Rifter, Fleet Rifter]
+
+200mm AutoCannon II x3
+EMP S x200
+`, w.Body.String())
+
+	w = export("multibuy")
+	assert.Equal(t, "Rifter 1\n200mm AutoCannon II 3\nEMP S 200\n", w.Body.String())
+
+	r := httptest.NewRequest("GET", "/a/abc", nil)
+	r.Header.Set("format", "")
+	assert.False(t, ctx.export(httptest.NewRecorder(), r, "appraisal-abc", nil))
+}
@@ -19,6 +19,8 @@ import (
 	"github.com/evepraisal/go-evepraisal/esi"
 	"github.com/evepraisal/go-evepraisal/legacy"
 	"github.com/evepraisal/go-evepraisal/discord"
+	"github.com/evepraisal/go-evepraisal/parsers"
+	"github.com/evepraisal/go-evepraisal/spreadsheet"
 	"github.com/go-zoo/bone"
 	"github.com/dustin/go-humanize"
 	"github.com/spf13/viper"
@@ -28,7 +30,13 @@ var (
 	errInputTooBig = errors.New("Input value is too big")
 	errInputEmpty  = errors.New("Input value is empty")
 
-	appraisalBodySizeLimit = int64(20 * 1000)
+	appraisalBodySizeLimit  = int64(20 * 1000)
+	appraisalTableSizeLimit = int64(5 * 1000 * 1000)
+
+	// appraisalRawSizeLimit is the most text an appraisal can be made from. An uploaded file can be bigger
+	// (xlsx files are mostly markup), but the rows in it are held to the same limit as a paste.
+	appraisalRawSizeLimit = 200000
+	appraisalRowLimit     = 10000
 )
 
 // AppraisalPage contains data used on the appraisal page
@@ -96,7 +104,7 @@ func parseAppraisalBody(r *http.Request) (string, error) {
 		body = getRequestParam(r, "raw_textarea")
 	}
 
-	if len(body) > 200000 {
+	if len(body) > appraisalRawSizeLimit {
 		return "", errInputTooBig
 	}
 
@@ -106,17 +114,121 @@ func parseAppraisalBody(r *http.Request) (string, error) {
 	return body, nil
 }
 
+// appraisalTable is an uploaded spreadsheet along with the columns to use from it
+type appraisalTable struct {
+	Rows    [][]string
+	Columns parsers.TableColumns
+}
+
+// parseAppraisalTable returns the rows of an uploaded CSV, TSV or XLSX file. It returns nil if the request
+// doesn't contain one, in which case the upload should be treated like pasted text.
+func parseAppraisalTable(r *http.Request) (*appraisalTable, error) {
+	var (
+		f           io.ReadCloser
+		filename    string
+		contentType string
+	)
+
+	if isMultiPart(r) {
+		r.ParseMultipartForm(appraisalBodySizeLimit)
+		file, header, err := r.FormFile("uploadappraisal")
+		if err == http.ErrMissingFile || err == http.ErrNotMultipart {
+			return nil, nil
+		} else if err != nil {
+			return nil, err
+		}
+		f = file
+		filename = header.Filename
+		contentType = header.Header.Get("Content-Type")
+	} else if !isURLEncodedFormData(r) {
+		// The body can only be read once, so only take it when the content type says it's a spreadsheet
+		contentType = r.Header.Get("Content-Type")
+		if spreadsheet.Detect("", contentType, nil) == spreadsheet.Unknown {
+			return nil, nil
+		}
+		f = r.Body
+	} else {
+		return nil, nil
+	}
+	defer f.Close()
+
+	data, err := ioutil.ReadAll(io.LimitReader(f, appraisalTableSizeLimit+1))
+	if err != nil {
+		return nil, err
+	}
+
+	format := spreadsheet.Detect(filename, contentType, data)
+	if format == spreadsheet.Unknown {
+		return nil, nil
+	}
+	if int64(len(data)) > appraisalTableSizeLimit {
+		return nil, errInputTooBig
+	}
+
+	rows, err := spreadsheet.Read(format, data, appraisalRowLimit)
+	if err == spreadsheet.ErrTooManyRows || err == spreadsheet.ErrTooManyColumns {
+		return nil, errInputTooBig
+	} else if err != nil {
+		return nil, fmt.Errorf("Unable to read %s file: %s", format, err)
+	}
+	if len(rows) == 0 {
+		return nil, errInputEmpty
+	}
+	if tableRawSize(rows) > appraisalRawSizeLimit {
+		return nil, errInputTooBig
+	}
+
+	table := &appraisalTable{Rows: rows, Columns: parsers.UnknownTableColumns}
+	for param, column := range map[string]*int{
+		"name_column":     &table.Columns.Name,
+		"typeid_column":   &table.Columns.TypeID,
+		"quantity_column": &table.Columns.Quantity,
+	} {
+		value := getRequestParam(r, param)
+		if value == "" {
+			continue
+		}
+		idx, ok := parsers.TableColumnIndex(rows, value)
+		if !ok {
+			return nil, fmt.Errorf("Column %q for %s not found", value, param)
+		}
+		*column = idx
+	}
+	return table, nil
+}
+
+// tableRawSize is the length of the rows joined with tabs and newlines, which is how they're stored in Raw
+func tableRawSize(rows [][]string) int {
+	size := 0
+	for _, row := range rows {
+		size++
+		for _, cell := range row {
+			size += len(cell) + 1
+		}
+	}
+	return size
+}
+
 // HandleAppraisal is the handler for POST /appraisal
 func (ctx *Context) HandleAppraisal(w http.ResponseWriter, r *http.Request) {
 
 	persist := r.FormValue("persist") != "no"
 
-	body, err := parseAppraisalBody(r)
+	table, err := parseAppraisalTable(r)
 	if err != nil {
 		ctx.renderErrorPage(r, w, http.StatusBadRequest, "Invalid input", err.Error())
 		return
 	}
 
+	var body string
+	if table == nil {
+		body, err = parseAppraisalBody(r)
+		if err != nil {
+			ctx.renderErrorPage(r, w, http.StatusBadRequest, "Invalid input", err.Error())
+			return
+		}
+	}
+
 	errorRoot := PageRoot{}
 	errorRoot.UI.RawTextAreaDefault = body
 
@@ -141,14 +253,7 @@ func (ctx *Context) HandleAppraisal(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Invalid market given
-	foundMarket := false
-	for _, m := range selectableMarkets {
-		if m.Name == market {
-			foundMarket = true
-			break
-		}
-	}
-	if !foundMarket {
+	if !IsSelectableMarket(market) {
 		ctx.renderErrorPageWithRoot(r, w, http.StatusBadRequest, "Invalid input", "Given market is not valid.", errorRoot)
 		return
 	}
@@ -184,7 +289,12 @@ func (ctx *Context) HandleAppraisal(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Actually do the appraisal
-	appraisal, err := ctx.App.StringToAppraisal(market, body)
+	var appraisal *evepraisal.Appraisal
+	if table != nil {
+		appraisal, err = ctx.App.TableToAppraisal(market, table.Rows, table.Columns)
+	} else {
+		appraisal, err = ctx.App.StringToAppraisal(market, body)
+	}
 	if err == evepraisal.ErrNoValidLinesFound {
 		log.Println("No valid lines found:", spew.Sdump(body))
 		ctx.renderErrorPageWithRoot(r, w, http.StatusBadRequest, "Invalid input", err.Error(), errorRoot)
@@ -194,6 +304,11 @@ func (ctx *Context) HandleAppraisal(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Which parser took each line is only kept when asked for
+	if getRequestParam(r, "debug") != "parse" {
+		appraisal.Parse = nil
+	}
+
 	appraisal.BuybackCap = buybackCap
 	appraisal.User = user
 	appraisal.Private = private
@@ -222,6 +337,8 @@ func (ctx *Context) HandleAppraisal(w http.ResponseWriter, r *http.Request) {
 	sort.Slice(appraisal.Original.Items, func(i, j int) bool {
 		return appraisal.Original.Items[i].RepresentativePrice() > appraisal.Original.Items[j].RepresentativePrice()
 	})
+	addLootSplit(r, appraisal)
+	estimateSurvey(r, appraisal)
 
 	var status *esi.ContractStatus = nil
 	if user != nil && appraisal.OwnerID == user.CharacterID {
@@ -279,6 +396,9 @@ func (ctx *Context) HandleViewAppraisal(w http.ResponseWriter, r *http.Request)
 	}
 
 	appraisal = cleanAppraisal(appraisal)
+	ctx.App.ResolvePinnedTypes(appraisal)
+	addLootSplit(r, appraisal)
+	estimateSurvey(r, appraisal)
 
 	sort.Slice(appraisal.Original.Items, func(i, j int) bool {
 		return appraisal.Original.Items[i].RepresentativePrice() > appraisal.Original.Items[j].RepresentativePrice()
@@ -295,6 +415,10 @@ func (ctx *Context) HandleViewAppraisal(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
+	if ctx.export(w, r, "appraisal-"+appraisal.ID, []evepraisal.Appraisal{*appraisal}) {
+		return
+	}
+
 	var status *esi.ContractStatus = nil
 	if user != nil && appraisal.OwnerID == user.CharacterID {
 		status = esi.NewOauthFetcher(ctx.App.TypeDB, ctx.OauthClient(r)).GetContractStatus(user, appraisal)
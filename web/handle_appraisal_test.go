@@ -0,0 +1,53 @@
+package web
+
+import (
+	"bytes"
+	"mime/multipart"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/evepraisal/go-evepraisal/parsers"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseAppraisalTable(t *testing.T) {
+	upload := func(filename string, content string, query string) (*appraisalTable, error) {
+		var body bytes.Buffer
+		w := multipart.NewWriter(&body)
+		f, err := w.CreateFormFile("uploadappraisal", filename)
+		assert.NoError(t, err)
+		f.Write([]byte(content))
+		w.Close()
+
+		r := httptest.NewRequest("POST", "/appraisal?"+query, &body)
+		r.Header.Set("Content-Type", w.FormDataContentType())
+		return parseAppraisalTable(r)
+	}
+
+	table, err := upload("inventory.csv", "Item,Qty\nTritanium,5\n", "quantity_column=qty")
+	assert.NoError(t, err)
+	assert.Equal(t, &appraisalTable{
+		Rows:    [][]string{{"Item", "Qty"}, {"Tritanium", "5"}},
+		Columns: parsers.TableColumns{Name: -1, TypeID: -1, Quantity: 1},
+	}, table)
+
+	_, err = upload("inventory.csv", "Item,Qty\nTritanium,5\n", "name_column=Price")
+	assert.Error(t, err)
+
+	// Uploads are held to the same limits as a paste
+	_, err = upload("inventory.csv", strings.Repeat("Tritanium,5\n", appraisalRowLimit+1), "")
+	assert.Equal(t, errInputTooBig, err)
+	_, err = upload("inventory.csv", "Item,Qty\n"+strings.Repeat("x", appraisalRawSizeLimit)+",5\n", "")
+	assert.Equal(t, errInputTooBig, err)
+
+	table, err = upload("paste.txt", "Tritanium 5", "")
+	assert.NoError(t, err)
+	assert.Nil(t, table)
+
+	r := httptest.NewRequest("POST", "/appraisal", strings.NewReader("Tritanium\t5\n"))
+	r.Header.Set("Content-Type", "text/tab-separated-values")
+	table, err = parseAppraisalTable(r)
+	assert.NoError(t, err)
+	assert.Equal(t, [][]string{{"Tritanium", "5"}}, table.Rows)
+}
@@ -0,0 +1,23 @@
+package web
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// HandleDebugParse is the handler for /debug/parse. It shows which parser took each line of the input and why,
+// without appraising anything.
+func (ctx *Context) HandleDebugParse(w http.ResponseWriter, r *http.Request) {
+	body, err := parseAppraisalBody(r)
+	if err != nil {
+		w.Header().Add("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(struct {
+			Error string `json:"error"`
+		}{Error: err.Error()})
+		return
+	}
+
+	w.Header().Add("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(ctx.App.ParseString(body))
+}
@@ -36,6 +36,10 @@ func (ctx *Context) HandleUserHistoryAppraisals(w http.ResponseWriter, r *http.R
 		cleanAppraisals = cleanAppraisals[0:limit]
 	}
 
+	if ctx.export(w, r, "history", cleanAppraisals) {
+		return
+	}
+
 	var history = make([]StatusedAppraisal,len(cleanAppraisals))
 	if len(history) > 0 {
 		cf := esi.NewOauthFetcher(ctx.App.TypeDB, ctx.OauthClient(r))
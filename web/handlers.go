@@ -8,6 +8,7 @@ import (
 
 	"github.com/NYTimes/gziphandler"
 	"github.com/elazarl/go-bindata-assetfs"
+	"github.com/evepraisal/go-evepraisal"
 	"github.com/go-zoo/bone"
 	"github.com/gorilla/context"
 	"github.com/mash/go-accesslog"
@@ -90,6 +91,10 @@ func (ctx *Context) HTTPHandler() http.Handler {
 	// View Item
 	router.GetFunc("/item/#typeID^[0-9]$", ctx.HandleViewItem)
 
+	// Parser debugging
+	router.GetFunc("/debug/parse", ctx.HandleDebugParse)
+	router.PostFunc("/debug/parse", ctx.HandleDebugParse)
+
 	// Search
 	router.GetFunc("/search", ctx.HandleSearch)
 
@@ -175,6 +180,13 @@ func (ctx *Context) HTTPHandler() http.Handler {
 				r.Header.Set("format", "raw")
 			} else {
 				r.Header.Set("format", "")
+				for _, exporter := range evepraisal.Exporters() {
+					if strings.HasSuffix(r.URL.Path, "."+exporter.Suffix) {
+						r.URL.Path = strings.TrimSuffix(r.URL.Path, "."+exporter.Suffix)
+						r.Header.Set("format", exporter.Suffix)
+						break
+					}
+				}
 			}
 			next.ServeHTTP(w, r)
 		})
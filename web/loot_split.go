@@ -0,0 +1,26 @@
+package web
+
+import (
+	"net/http"
+
+	"github.com/evepraisal/go-evepraisal"
+	"github.com/evepraisal/go-evepraisal/parsers"
+)
+
+// lootSplitRules reads the loot split rules from the request. "participants" is a participation list, "tax" is
+// the corp tax in percent and "reserve" is the ISK that is set aside for the pilot named in "fc".
+func lootSplitRules(r *http.Request) evepraisal.LootSplitRules {
+	return evepraisal.LootSplitRules{
+		Participants: evepraisal.ParseParticipationList(getRequestParam(r, "participants")),
+		TaxPercent:   parsers.ToDecimal(getRequestParam(r, "tax")),
+		Reserve:      parsers.ToDecimal(getRequestParam(r, "reserve")),
+		FC:           getRequestParam(r, "fc"),
+	}
+}
+
+// addLootSplit splits the loot of appraisals with items that belong to different pilots
+func addLootSplit(r *http.Request, appraisal *evepraisal.Appraisal) {
+	if appraisal.HasPlayers() {
+		appraisal.LootSplit = appraisal.SplitLoot(lootSplitRules(r))
+	}
+}
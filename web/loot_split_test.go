@@ -0,0 +1,44 @@
+package web
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/evepraisal/go-evepraisal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAddLootSplit(t *testing.T) {
+	appraisal := &evepraisal.Appraisal{}
+	appraisal.Original.Items = []evepraisal.AppraisalItem{{Name: "Loot", Quantity: 10}}
+	appraisal.Original.Items[0].Prices.Sell.Min = 100
+	appraisal.Groups = []evepraisal.ItemGroup{
+		{Name: "Alice", Player: "Alice", Totals: evepraisal.Totals{Sell: 900}},
+		{Name: "Bob", Player: "Bob", Totals: evepraisal.Totals{Sell: 100}},
+	}
+
+	// Everyone who looted gets the same share
+	addLootSplit(httptest.NewRequest("GET", "/a/abc", nil), appraisal)
+	if assert.Len(t, appraisal.LootSplit.Pilots, 2) {
+		assert.Equal(t, evepraisal.LootSplitPilot{Name: "Alice", Share: 0.5, Looted: 900, Owed: 500, Balance: -400}, appraisal.LootSplit.Pilots[0])
+		assert.Equal(t, evepraisal.LootSplitPilot{Name: "Bob", Share: 0.5, Looted: 100, Owed: 500, Balance: 400}, appraisal.LootSplit.Pilots[1])
+	}
+
+	// 10% tax, 100 ISK for the FC and the rest by participation
+	r := httptest.NewRequest("GET", "/a/abc?tax=10&reserve=100&fc=Carol&participants=Alice%0ABob%2050%25%0ACarol%090", nil)
+	addLootSplit(r, appraisal)
+	assert.Equal(t, 100.0, appraisal.LootSplit.Tax)
+	assert.Equal(t, 100.0, appraisal.LootSplit.Reserve)
+	if assert.Len(t, appraisal.LootSplit.Pilots, 3) {
+		assert.Equal(t, "Alice", appraisal.LootSplit.Pilots[0].Name)
+		assert.InDelta(t, 533.33, appraisal.LootSplit.Pilots[0].Owed, 0.01)
+		assert.InDelta(t, 266.67, appraisal.LootSplit.Pilots[1].Owed, 0.01)
+		assert.Equal(t, evepraisal.LootSplitPilot{Name: "Carol", Owed: 100, Balance: 100}, appraisal.LootSplit.Pilots[2])
+	}
+
+	// Appraisals without pilots aren't split
+	appraisal = &evepraisal.Appraisal{}
+	appraisal.Original.Items = []evepraisal.AppraisalItem{{Name: "Loot", Quantity: 1}}
+	addLootSplit(httptest.NewRequest("GET", "/a/abc", nil), appraisal)
+	assert.Nil(t, appraisal.LootSplit)
+}
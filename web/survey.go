@@ -0,0 +1,35 @@
+package web
+
+import (
+	"net/http"
+
+	"github.com/evepraisal/go-evepraisal"
+	"github.com/evepraisal/go-evepraisal/parsers"
+)
+
+// surveySettings reads the mining ship from the request. "ship" names it, "yield" is the m3 it mines per cycle,
+// "cycle" is the cycle time in seconds and "refine" is the refine rate in percent. Anything that isn't given is
+// taken from the config.
+func surveySettings(r *http.Request) evepraisal.SurveySettings {
+	settings := evepraisal.DefaultSurveySettings()
+	if ship := getRequestParam(r, "ship"); ship != "" {
+		settings.Ship = ship
+	}
+	if yield := parsers.ToDecimal(getRequestParam(r, "yield")); yield > 0 {
+		settings.Yield = yield
+	}
+	if cycleTime := parsers.ToDecimal(getRequestParam(r, "cycle")); cycleTime > 0 {
+		settings.CycleTime = cycleTime
+	}
+	if refineRate := parsers.ToDecimal(getRequestParam(r, "refine")); refineRate > 0 {
+		settings.RefineRate = refineRate
+	}
+	return settings
+}
+
+// estimateSurvey works out the survey scan estimate of an appraisal for the ship in the request
+func estimateSurvey(r *http.Request, appraisal *evepraisal.Appraisal) {
+	if appraisal.Survey != nil {
+		appraisal.Survey.Estimate(surveySettings(r))
+	}
+}
@@ -0,0 +1,38 @@
+package web
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/evepraisal/go-evepraisal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEstimateSurvey(t *testing.T) {
+	appraisal := &evepraisal.Appraisal{
+		Survey: &evepraisal.SurveyEstimate{
+			Rocks: []evepraisal.SurveyRock{
+				{Name: "Veldspar", Quantity: 10000, UnitVolume: 0.1, MineralValue: 10},
+				{Name: "Scordite", Quantity: 1000, UnitVolume: 0.15, MineralValue: 30},
+			},
+		},
+	}
+
+	estimateSurvey(httptest.NewRequest("GET", "/a/abc?ship=Venture&yield=100&cycle=60&refine=50", nil), appraisal)
+	survey := appraisal.Survey
+	assert.Equal(t, evepraisal.SurveySettings{Ship: "Venture", Yield: 100, CycleTime: 60, RefineRate: 50}, survey.Settings)
+	if assert.Len(t, survey.Rocks, 2) {
+		// Scordite has the most ISK per m3 so it comes first
+		assert.Equal(t, "Scordite", survey.Rocks[0].Name)
+		assert.InDelta(t, 100.0, survey.Rocks[0].ISKPerM3, 0.001)
+		assert.InDelta(t, 15000.0, survey.Rocks[0].Value, 0.001)
+		assert.InDelta(t, 1.5, survey.Rocks[0].Cycles, 0.001)
+		assert.InDelta(t, 10000.0, survey.Rocks[0].ValuePerCycle, 0.001)
+		assert.InDelta(t, 600000.0, survey.Rocks[0].ValuePerHour, 0.001)
+		assert.Equal(t, "Veldspar", survey.Rocks[1].Name)
+		assert.InDelta(t, 50.0, survey.Rocks[1].ISKPerM3, 0.001)
+	}
+	assert.InDelta(t, 1150.0, survey.Volume, 0.001)
+	assert.InDelta(t, 65000.0, survey.Value, 0.001)
+	assert.InDelta(t, 11.5/60, survey.Hours, 0.001)
+}
@@ -49,6 +49,16 @@ var selectableMarkets = []namedThing{
 	//{Name: "rens", DisplayName: "Rens"},
 }
 
+// IsSelectableMarket returns true if the market, by its lower case name, is one that appraisals can be made in
+func IsSelectableMarket(name string) bool {
+	for _, m := range selectableMarkets {
+		if m.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
 var selectableVisibilities = []namedThing{
 	{Name: "public", DisplayName: "Public"},
 	{Name: "private", DisplayName: "Private"},